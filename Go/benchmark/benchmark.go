@@ -2,12 +2,16 @@ package main
 
 import (
 	"bufio"
+	"container/heap"
+	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	ds "benchmark/datastructures"
@@ -430,9 +434,24 @@ func (bs *BenchmarkSuite) benchmarkDLLFind(n int) BenchmarkResult {
 // STACK BENCHMARKS
 // ============================================================================
 
-func (bs *BenchmarkSuite) benchmarkStackPush(n int) BenchmarkResult {
+// newMyStack returns a node-based stack, or one stored in a Deque.
+func newMyStack(onDeque bool) *ds.MyStack {
+	if onDeque {
+		return ds.NewMyStackOnDeque()
+	}
+	return ds.NewMyStack()
+}
+
+func stackName(onDeque bool) string {
+	if onDeque {
+		return "Stack/Deque"
+	}
+	return "Stack"
+}
+
+func (bs *BenchmarkSuite) benchmarkStackPush(n int, onDeque bool) BenchmarkResult {
 	data := generateRandomData(n)
-	stack := ds.NewMyStack()
+	stack := newMyStack(onDeque)
 
 	memBefore := getMemoryUsage()
 	start := time.Now()
@@ -446,7 +465,7 @@ func (bs *BenchmarkSuite) benchmarkStackPush(n int) BenchmarkResult {
 
 	return BenchmarkResult{
 		Operation:     "Push",
-		DataStructure: "Stack",
+		DataStructure: stackName(onDeque),
 		NumElements:   n,
 		Duration:      duration,
 		OpsPerSecond:  float64(n) / duration.Seconds(),
@@ -454,8 +473,8 @@ func (bs *BenchmarkSuite) benchmarkStackPush(n int) BenchmarkResult {
 	}
 }
 
-func (bs *BenchmarkSuite) benchmarkStackPop(n int) BenchmarkResult {
-	stack := ds.NewMyStack()
+func (bs *BenchmarkSuite) benchmarkStackPop(n int, onDeque bool) BenchmarkResult {
+	stack := newMyStack(onDeque)
 	for i := 0; i < n; i++ {
 		stack.Push(i)
 	}
@@ -470,7 +489,7 @@ func (bs *BenchmarkSuite) benchmarkStackPop(n int) BenchmarkResult {
 
 	return BenchmarkResult{
 		Operation:     "Pop",
-		DataStructure: "Stack",
+		DataStructure: stackName(onDeque),
 		NumElements:   n,
 		Duration:      duration,
 		OpsPerSecond:  float64(n) / duration.Seconds(),
@@ -478,8 +497,8 @@ func (bs *BenchmarkSuite) benchmarkStackPop(n int) BenchmarkResult {
 	}
 }
 
-func (bs *BenchmarkSuite) benchmarkStackPeek(n int) BenchmarkResult {
-	stack := ds.NewMyStack()
+func (bs *BenchmarkSuite) benchmarkStackPeek(n int, onDeque bool) BenchmarkResult {
+	stack := newMyStack(onDeque)
 	for i := 0; i < n; i++ {
 		stack.Push(i)
 	}
@@ -495,7 +514,7 @@ func (bs *BenchmarkSuite) benchmarkStackPeek(n int) BenchmarkResult {
 
 	return BenchmarkResult{
 		Operation:     "Peek",
-		DataStructure: "Stack",
+		DataStructure: stackName(onDeque),
 		NumElements:   peekCount,
 		Duration:      duration,
 		OpsPerSecond:  float64(peekCount) / duration.Seconds(),
@@ -507,9 +526,24 @@ func (bs *BenchmarkSuite) benchmarkStackPeek(n int) BenchmarkResult {
 // QUEUE BENCHMARKS
 // ============================================================================
 
-func (bs *BenchmarkSuite) benchmarkQueuePush(n int) BenchmarkResult {
+// newMyQueue returns a node-based queue, or one stored in a Deque.
+func newMyQueue(onDeque bool) *ds.MyQueue {
+	if onDeque {
+		return ds.NewMyQueueOnDeque()
+	}
+	return ds.NewMyQueue()
+}
+
+func queueName(onDeque bool) string {
+	if onDeque {
+		return "Queue/Deque"
+	}
+	return "Queue"
+}
+
+func (bs *BenchmarkSuite) benchmarkQueuePush(n int, onDeque bool) BenchmarkResult {
 	data := generateRandomData(n)
-	queue := ds.NewMyQueue()
+	queue := newMyQueue(onDeque)
 
 	memBefore := getMemoryUsage()
 	start := time.Now()
@@ -523,7 +557,7 @@ func (bs *BenchmarkSuite) benchmarkQueuePush(n int) BenchmarkResult {
 
 	return BenchmarkResult{
 		Operation:     "Push",
-		DataStructure: "Queue",
+		DataStructure: queueName(onDeque),
 		NumElements:   n,
 		Duration:      duration,
 		OpsPerSecond:  float64(n) / duration.Seconds(),
@@ -531,8 +565,8 @@ func (bs *BenchmarkSuite) benchmarkQueuePush(n int) BenchmarkResult {
 	}
 }
 
-func (bs *BenchmarkSuite) benchmarkQueuePop(n int) BenchmarkResult {
-	queue := ds.NewMyQueue()
+func (bs *BenchmarkSuite) benchmarkQueuePop(n int, onDeque bool) BenchmarkResult {
+	queue := newMyQueue(onDeque)
 	for i := 0; i < n; i++ {
 		queue.Push(i)
 	}
@@ -547,7 +581,7 @@ func (bs *BenchmarkSuite) benchmarkQueuePop(n int) BenchmarkResult {
 
 	return BenchmarkResult{
 		Operation:     "Pop",
-		DataStructure: "Queue",
+		DataStructure: queueName(onDeque),
 		NumElements:   n,
 		Duration:      duration,
 		OpsPerSecond:  float64(n) / duration.Seconds(),
@@ -555,8 +589,8 @@ func (bs *BenchmarkSuite) benchmarkQueuePop(n int) BenchmarkResult {
 	}
 }
 
-func (bs *BenchmarkSuite) benchmarkQueuePeek(n int) BenchmarkResult {
-	queue := ds.NewMyQueue()
+func (bs *BenchmarkSuite) benchmarkQueuePeek(n int, onDeque bool) BenchmarkResult {
+	queue := newMyQueue(onDeque)
 	for i := 0; i < n; i++ {
 		queue.Push(i)
 	}
@@ -572,7 +606,7 @@ func (bs *BenchmarkSuite) benchmarkQueuePeek(n int) BenchmarkResult {
 
 	return BenchmarkResult{
 		Operation:     "Peek",
-		DataStructure: "Queue",
+		DataStructure: queueName(onDeque),
 		NumElements:   peekCount,
 		Duration:      duration,
 		OpsPerSecond:  float64(peekCount) / duration.Seconds(),
@@ -667,9 +701,18 @@ func (bs *BenchmarkSuite) benchmarkHashChainRemove(n int) BenchmarkResult {
 // HASH TABLE (OPEN ADDRESSING) BENCHMARKS
 // ============================================================================
 
-func (bs *BenchmarkSuite) benchmarkHashOpenInsert(n int) BenchmarkResult {
+// hashOpenName labels results with the probing strategy unless it is the
+// default one.
+func hashOpenName(opts []ds.HashTableOpenOptions) string {
+	if len(opts) == 0 || opts[len(opts)-1].Probing == ds.LinearProbing {
+		return "HashTableOpen"
+	}
+	return "Open/" + opts[len(opts)-1].Probing.String()
+}
+
+func (bs *BenchmarkSuite) benchmarkHashOpenInsert(n int, opts ...ds.HashTableOpenOptions) BenchmarkResult {
 	data := generateRandomData(n)
-	ht := ds.NewHashTableOpen(n / 4)
+	ht := ds.NewHashTableOpen(n/4, opts...)
 
 	memBefore := getMemoryUsage()
 	start := time.Now()
@@ -683,7 +726,7 @@ func (bs *BenchmarkSuite) benchmarkHashOpenInsert(n int) BenchmarkResult {
 
 	return BenchmarkResult{
 		Operation:     "Insert",
-		DataStructure: "HashTableOpen",
+		DataStructure: hashOpenName(opts),
 		NumElements:   n,
 		Duration:      duration,
 		OpsPerSecond:  float64(n) / duration.Seconds(),
@@ -691,8 +734,8 @@ func (bs *BenchmarkSuite) benchmarkHashOpenInsert(n int) BenchmarkResult {
 	}
 }
 
-func (bs *BenchmarkSuite) benchmarkHashOpenGet(n int) BenchmarkResult {
-	ht := ds.NewHashTableOpen(n / 4)
+func (bs *BenchmarkSuite) benchmarkHashOpenGet(n int, opts ...ds.HashTableOpenOptions) BenchmarkResult {
+	ht := ds.NewHashTableOpen(n/4, opts...)
 	for i := 0; i < n; i++ {
 		ht.Insert(i, i*2)
 	}
@@ -713,37 +756,668 @@ func (bs *BenchmarkSuite) benchmarkHashOpenGet(n int) BenchmarkResult {
 
 	return BenchmarkResult{
 		Operation:     "Get",
-		DataStructure: "HashTableOpen",
+		DataStructure: hashOpenName(opts),
 		NumElements:   getCount,
 		Duration:      duration,
 		OpsPerSecond:  float64(getCount) / duration.Seconds(),
 		MemoryUsed:    0,
 	}
-}
+}
+
+func (bs *BenchmarkSuite) benchmarkHashOpenRemove(n int, opts ...ds.HashTableOpenOptions) BenchmarkResult {
+	ht := ds.NewHashTableOpen(n/4, opts...)
+	for i := 0; i < n; i++ {
+		ht.Insert(i, i*2)
+	}
+
+	removeCount := n / 2
+	start := time.Now()
+
+	for i := 0; i < removeCount; i++ {
+		ht.Remove(i)
+	}
+
+	duration := time.Since(start)
+
+	return BenchmarkResult{
+		Operation:     "Remove",
+		DataStructure: hashOpenName(opts),
+		NumElements:   removeCount,
+		Duration:      duration,
+		OpsPerSecond:  float64(removeCount) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// generateClusteredData returns keys in dense runs of 64 consecutive
+// values, 1000 apart, so neighbouring home slots fill up together.
+func generateClusteredData(n int) []int {
+	data := make([]int, n)
+	runLength := 64
+	for i := range data {
+		data[i] = (i/runLength)*1000 + i%runLength
+	}
+	rand.Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+	return data
+}
+
+// benchmarkHashOpenProbing inserts, looks up and removes clustered keys
+// with the given probing strategy.
+func (bs *BenchmarkSuite) benchmarkHashOpenProbing(n int, strategy ds.ProbeStrategy) []BenchmarkResult {
+	keys := generateClusteredData(n)
+	ht := ds.NewHashTableOpen(16, ds.HashTableOpenOptions{Probing: strategy})
+	name := "Open/" + strategy.String()
+
+	memBefore := getMemoryUsage()
+	start := time.Now()
+	for i, k := range keys {
+		ht.Insert(k, i)
+	}
+	insertDuration := time.Since(start)
+	memAfter := getMemoryUsage()
+
+	start = time.Now()
+	for _, k := range keys {
+		ht.Get(k)
+	}
+	getDuration := time.Since(start)
+
+	stats := ht.ProbeStats()
+	fmt.Printf("   %-22s avg probe %.2f, max probe %d\n", name, stats.AverageDistance, stats.MaxDistance)
+
+	start = time.Now()
+	for _, k := range keys[:n/2] {
+		ht.Remove(k)
+	}
+	removeDuration := time.Since(start)
+
+	return []BenchmarkResult{
+		{
+			Operation:     "Insert Clustered",
+			DataStructure: name,
+			NumElements:   n,
+			Duration:      insertDuration,
+			OpsPerSecond:  float64(n) / insertDuration.Seconds(),
+			MemoryUsed:    calcMemoryDiff(memBefore, memAfter),
+		},
+		{
+			Operation:     "Get Clustered",
+			DataStructure: name,
+			NumElements:   n,
+			Duration:      getDuration,
+			OpsPerSecond:  float64(n) / getDuration.Seconds(),
+		},
+		{
+			Operation:     "Remove Clustered",
+			DataStructure: name,
+			NumElements:   n / 2,
+			Duration:      removeDuration,
+			OpsPerSecond:  float64(n/2) / removeDuration.Seconds(),
+		},
+	}
+}
+
+// ============================================================================
+// HASH TABLE (CUCKOO) BENCHMARKS
+// ============================================================================
+
+func (bs *BenchmarkSuite) benchmarkHashCuckooInsert(n int) BenchmarkResult {
+	data := generateRandomData(n)
+	ht := ds.NewHashTableCuckoo(n / 4)
+
+	memBefore := getMemoryUsage()
+	start := time.Now()
+
+	for i, v := range data {
+		ht.Insert(i, v)
+	}
+
+	duration := time.Since(start)
+	memAfter := getMemoryUsage()
+
+	return BenchmarkResult{
+		Operation:     "Insert",
+		DataStructure: "HashTableCuckoo",
+		NumElements:   n,
+		Duration:      duration,
+		OpsPerSecond:  float64(n) / duration.Seconds(),
+		MemoryUsed:    calcMemoryDiff(memBefore, memAfter),
+	}
+}
+
+func (bs *BenchmarkSuite) benchmarkHashCuckooGet(n int) BenchmarkResult {
+	ht := ds.NewHashTableCuckoo(n / 4)
+	for i := 0; i < n; i++ {
+		ht.Insert(i, i*2)
+	}
+
+	getCount := n
+	keys := generateRandomData(getCount)
+	for i := range keys {
+		keys[i] = keys[i] % (n * 2)
+	}
+
+	start := time.Now()
+
+	for _, key := range keys {
+		ht.Get(key)
+	}
+
+	duration := time.Since(start)
+
+	return BenchmarkResult{
+		Operation:     "Get",
+		DataStructure: "HashTableCuckoo",
+		NumElements:   getCount,
+		Duration:      duration,
+		OpsPerSecond:  float64(getCount) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+func (bs *BenchmarkSuite) benchmarkHashCuckooRemove(n int) BenchmarkResult {
+	ht := ds.NewHashTableCuckoo(n / 4)
+	for i := 0; i < n; i++ {
+		ht.Insert(i, i*2)
+	}
+
+	removeCount := n / 2
+	start := time.Now()
+
+	for i := 0; i < removeCount; i++ {
+		ht.Remove(i)
+	}
+
+	duration := time.Since(start)
+
+	return BenchmarkResult{
+		Operation:     "Remove",
+		DataStructure: "HashTableCuckoo",
+		NumElements:   removeCount,
+		Duration:      duration,
+		OpsPerSecond:  float64(removeCount) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// ============================================================================
+// CONCURRENT MAP BENCHMARKS
+// ============================================================================
+
+// lockedChain is the baseline ConcurrentMap is compared against: one
+// HashTableChain behind one RWMutex.
+type lockedChain struct {
+	mu    sync.RWMutex
+	table *ds.HashTableChain[int, int]
+}
+
+func (l *lockedChain) Load(key int) (int, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.table.Get(key)
+}
+
+func (l *lockedChain) Store(key, value int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.table.Put(key, value)
+}
+
+type concurrentStore interface {
+	Load(key int) (int, bool)
+	Store(key, value int)
+}
+
+// benchmarkParallelMixed runs n operations, 90% Load and 10% Store on
+// random keys, split across the given number of goroutines.
+func (bs *BenchmarkSuite) benchmarkParallelMixed(name string, m concurrentStore, n, goroutines int) BenchmarkResult {
+	for i := 0; i < n; i++ {
+		m.Store(i, i)
+	}
+
+	var wg sync.WaitGroup
+	perWorker := n / goroutines
+	start := time.Now()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < perWorker; i++ {
+				key := rng.Intn(n)
+				if rng.Intn(10) == 0 {
+					m.Store(key, i)
+				} else {
+					m.Load(key)
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+	duration := time.Since(start)
+
+	ops := perWorker * goroutines
+	return BenchmarkResult{
+		Operation:     fmt.Sprintf("Mixed x%d", goroutines),
+		DataStructure: name,
+		NumElements:   ops,
+		Duration:      duration,
+		OpsPerSecond:  float64(ops) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// ============================================================================
+// RESIZE LATENCY BENCHMARKS
+// ============================================================================
+
+// benchmarkWorstInsert inserts n sequential keys and reports the slowest
+// single Insert as the duration, which is where a full rehash shows up.
+func (bs *BenchmarkSuite) benchmarkWorstInsert(name string, insert func(key, value int), n int) BenchmarkResult {
+	var worst time.Duration
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		opStart := time.Now()
+		insert(i, i)
+		if d := time.Since(opStart); d > worst {
+			worst = d
+		}
+	}
+	total := time.Since(start)
+
+	return BenchmarkResult{
+		Operation:     "Worst Insert",
+		DataStructure: name,
+		NumElements:   n,
+		Duration:      worst,
+		OpsPerSecond:  float64(n) / total.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// ============================================================================
+// BLOCKING QUEUE BENCHMARKS
+// ============================================================================
+
+// benchmarkPipeline passes n items from `workers` producers to as many
+// consumers through a queue of the given capacity. put and take stand for
+// either a BlockingQueue or a channel; take reports false once the queue
+// is closed and empty.
+func (bs *BenchmarkSuite) benchmarkPipeline(name string, put func(int), take func() bool, closeQueue func(), n, workers int) BenchmarkResult {
+	var producers, consumers sync.WaitGroup
+	perWorker := n / workers
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		producers.Add(1)
+		go func() {
+			defer producers.Done()
+			for i := 0; i < perWorker; i++ {
+				put(i)
+			}
+		}()
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for take() {
+			}
+		}()
+	}
+	producers.Wait()
+	closeQueue()
+	consumers.Wait()
+	duration := time.Since(start)
+
+	ops := perWorker * workers
+	return BenchmarkResult{
+		Operation:     fmt.Sprintf("Pipeline x%d", workers),
+		DataStructure: name,
+		NumElements:   ops,
+		Duration:      duration,
+		OpsPerSecond:  float64(ops) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// ============================================================================
+// LOCK-FREE QUEUE BENCHMARKS
+// ============================================================================
+
+// lockedQueue is a MyQueue behind one mutex, the baseline for
+// LockFreeQueue.
+type lockedQueue struct {
+	mu    sync.Mutex
+	queue *ds.MyQueue
+}
+
+func (l *lockedQueue) Push(value int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queue.Push(value)
+}
+
+func (l *lockedQueue) TryPop() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	value, err := l.queue.Peek()
+	if err != nil {
+		return 0, false
+	}
+	l.queue.Pop()
+	return value, true
+}
+
+type concurrentQueue interface {
+	Push(value int)
+	TryPop() (int, bool)
+}
+
+// benchmarkContendedQueue splits n Push/TryPop pairs across the given
+// number of goroutines, all hitting the same queue.
+func (bs *BenchmarkSuite) benchmarkContendedQueue(name string, q concurrentQueue, n, goroutines int) BenchmarkResult {
+	var wg sync.WaitGroup
+	perWorker := n / goroutines
+	start := time.Now()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				q.Push(i)
+				q.TryPop()
+			}
+		}()
+	}
+	wg.Wait()
+	duration := time.Since(start)
+
+	ops := 2 * perWorker * goroutines
+	return BenchmarkResult{
+		Operation:     fmt.Sprintf("Push+Pop x%d", goroutines),
+		DataStructure: name,
+		NumElements:   ops,
+		Duration:      duration,
+		OpsPerSecond:  float64(ops) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// ============================================================================
+// PRIORITY QUEUE BENCHMARKS
+// ============================================================================
+
+// intHeap is the container/heap baseline for PriorityQueue.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// heapItem and itemHeap are the container/heap baseline for
+// IndexedPriorityQueue, following the package's own example.
+type heapItem struct {
+	value, priority, index int
+}
+
+type itemHeap []*heapItem
+
+func (h itemHeap) Len() int           { return len(h) }
+func (h itemHeap) Less(i, j int) bool { return h[i].priority < h[j].priority }
+func (h itemHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *itemHeap) Push(x any) {
+	item := x.(*heapItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+func (h *itemHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// benchmarkPQPushPop pushes n random ints and pops them all.
+func (bs *BenchmarkSuite) benchmarkPQPushPop(n int, useHeapPkg bool) BenchmarkResult {
+	data := generateRandomData(n)
+	name := "PriorityQueue"
+
+	start := time.Now()
+	if useHeapPkg {
+		name = "container/heap"
+		h := &intHeap{}
+		for _, v := range data {
+			heap.Push(h, v)
+		}
+		for h.Len() > 0 {
+			heap.Pop(h)
+		}
+	} else {
+		pq := ds.NewPriorityQueue()
+		for _, v := range data {
+			pq.Push(v)
+		}
+		for !pq.IsEmpty() {
+			pq.Pop()
+		}
+	}
+	duration := time.Since(start)
+
+	return BenchmarkResult{
+		Operation:     "Push+Pop",
+		DataStructure: name,
+		NumElements:   n,
+		Duration:      duration,
+		OpsPerSecond:  float64(2*n) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// benchmarkPQHeapify builds a heap from n random ints in one go.
+func (bs *BenchmarkSuite) benchmarkPQHeapify(n int, useHeapPkg bool) BenchmarkResult {
+	data := generateRandomData(n)
+	name := "PriorityQueue"
+
+	start := time.Now()
+	if useHeapPkg {
+		name = "container/heap"
+		h := intHeap(append([]int(nil), data...))
+		heap.Init(&h)
+	} else {
+		ds.NewPriorityQueueFrom(data, func(a, b int) bool { return a < b })
+	}
+	duration := time.Since(start)
+
+	return BenchmarkResult{
+		Operation:     "Heapify",
+		DataStructure: name,
+		NumElements:   n,
+		Duration:      duration,
+		OpsPerSecond:  float64(n) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// benchmarkPQUpdate changes the priority of n random items of a filled
+// queue, the decrease-key workload of Dijkstra's algorithm.
+func (bs *BenchmarkSuite) benchmarkPQUpdate(n int, useHeapPkg bool) BenchmarkResult {
+	data := generateRandomData(n)
+	name := "IndexedPQ"
+	var duration time.Duration
+
+	if useHeapPkg {
+		name = "container/heap"
+		h := &itemHeap{}
+		items := make([]*heapItem, n)
+		for i, v := range data {
+			items[i] = &heapItem{value: i, priority: v}
+			heap.Push(h, items[i])
+		}
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			item := items[data[i]%n]
+			item.priority = rand.Intn(n * 10)
+			heap.Fix(h, item.index)
+		}
+		duration = time.Since(start)
+	} else {
+		pq := ds.NewIndexedPriorityQueue()
+		items := make([]*ds.PQItem[int, int], n)
+		for i, v := range data {
+			items[i] = pq.Push(i, v)
+		}
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			pq.Update(items[data[i]%n], rand.Intn(n*10))
+		}
+		duration = time.Since(start)
+	}
+
+	return BenchmarkResult{
+		Operation:     "Update",
+		DataStructure: name,
+		NumElements:   n,
+		Duration:      duration,
+		OpsPerSecond:  float64(n) / duration.Seconds(),
+		MemoryUsed:    0,
+	}
+}
+
+// ============================================================================
+// CACHE BENCHMARKS
+// ============================================================================
+
+// cacheTrace is a sequence of keys to replay against each cache policy.
+type cacheTrace struct {
+	name string
+	keys []int
+}
+
+// cachePolicies lists the caches the hit ratio benchmark compares.
+var cachePolicies = []struct {
+	name string
+	new  func(capacity int) ds.Cache[int, int]
+}{
+	{"LRUCache", func(capacity int) ds.Cache[int, int] { return ds.NewLRUCache(capacity) }},
+	{"LFUCache", func(capacity int) ds.Cache[int, int] { return ds.NewLFUCache(capacity) }},
+	{"ARCCache", func(capacity int) ds.Cache[int, int] { return ds.NewARCCache(capacity) }},
+}
+
+// hitRatioRow holds the hit ratio of every policy on one trace, in the
+// order of cachePolicies.
+type hitRatioRow struct {
+	trace    string
+	capacity int
+	ratios   []float64
+}
+
+// generateCacheTraces builds n requests for each of four access patterns,
+// scaled to a cache of capacity entries:
+//   - Zipf: a skewed popularity that never changes
+//   - Zipf+scans: the same, interrupted by one-off scans of new keys
+//   - Loop: a cycle a quarter longer than the cache, the LRU worst case
+//   - Shifting: a hot set that moves every quarter, the LFU worst case
+func generateCacheTraces(n, capacity int) []cacheTrace {
+	rng := rand.New(rand.NewSource(42))
+	zipf := rand.NewZipf(rng, 1.1, 1, uint64(capacity*100))
+
+	zipfKeys := make([]int, n)
+	for i := range zipfKeys {
+		zipfKeys[i] = int(zipf.Uint64())
+	}
+
+	scanKeys := make([]int, 0, n)
+	nextScanKey := capacity * 1000
+	for len(scanKeys) < n {
+		for i := 0; i < 8*capacity && len(scanKeys) < n; i++ {
+			scanKeys = append(scanKeys, int(zipf.Uint64()))
+		}
+		for i := 0; i < 2*capacity && len(scanKeys) < n; i++ {
+			scanKeys = append(scanKeys, nextScanKey)
+			nextScanKey++
+		}
+	}
+
+	loopKeys := make([]int, n)
+	for i := range loopKeys {
+		loopKeys[i] = i % (capacity + capacity/4)
+	}
+
+	shiftKeys := make([]int, n)
+	hotSet := max(capacity/2, 1)
+	for i := range shiftKeys {
+		phase := i / max(n/4, 1)
+		if rng.Intn(10) < 9 {
+			shiftKeys[i] = phase*capacity*1000 + rng.Intn(hotSet)
+		} else {
+			shiftKeys[i] = phase*capacity*1000 + hotSet + rng.Intn(capacity*100)
+		}
+	}
+
+	return []cacheTrace{
+		{"Zipf", zipfKeys},
+		{"Zipf+scans", scanKeys},
+		{"Loop", loopKeys},
+		{"Shifting", shiftKeys},
+	}
+}
+
+// loadCacheTrace reads a trace file of integer keys separated by
+// whitespace.
+func loadCacheTrace(filename string) (cacheTrace, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return cacheTrace{}, err
+	}
+	defer file.Close()
 
-func (bs *BenchmarkSuite) benchmarkHashOpenRemove(n int) BenchmarkResult {
-	ht := ds.NewHashTableOpen(n / 4)
-	for i := 0; i < n; i++ {
-		ht.Insert(i, i*2)
+	trace := cacheTrace{name: filename}
+	scanner := bufio.NewScanner(file)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		key, err := strconv.Atoi(scanner.Text())
+		if err != nil {
+			return cacheTrace{}, fmt.Errorf("bad key %q: %w", scanner.Text(), err)
+		}
+		trace.keys = append(trace.keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return cacheTrace{}, err
 	}
+	if len(trace.keys) == 0 {
+		return cacheTrace{}, fmt.Errorf("%s has no keys", filename)
+	}
+	return trace, nil
+}
 
-	removeCount := n / 2
+// benchmarkCacheTrace replays trace against cache the way a read-through
+// cache is used: a Get for every key, followed by a Put on a miss.
+func (bs *BenchmarkSuite) benchmarkCacheTrace(name string, cache ds.Cache[int, int], trace cacheTrace) (BenchmarkResult, ds.CacheStats) {
 	start := time.Now()
-
-	for i := 0; i < removeCount; i++ {
-		ht.Remove(i)
+	for _, key := range trace.keys {
+		if _, ok := cache.Get(key); !ok {
+			cache.Put(key, key)
+		}
 	}
-
 	duration := time.Since(start)
 
 	return BenchmarkResult{
-		Operation:     "Remove",
-		DataStructure: "HashTableOpen",
-		NumElements:   removeCount,
+		Operation:     trace.name,
+		DataStructure: fmt.Sprintf("%s(%d)", name, cache.Cap()),
+		NumElements:   len(trace.keys),
 		Duration:      duration,
-		OpsPerSecond:  float64(removeCount) / duration.Seconds(),
+		OpsPerSecond:  float64(len(trace.keys)) / duration.Seconds(),
 		MemoryUsed:    0,
-	}
+	}, cache.Stats()
 }
 
 // ============================================================================
@@ -798,6 +1472,38 @@ func (bs *BenchmarkSuite) benchmarkAVLInsertSequential(n int) BenchmarkResult {
 	}
 }
 
+// benchmarkSkipListInsert mirrors the two AVLTree insert benchmarks. The
+// level generator is seeded so runs are comparable.
+func (bs *BenchmarkSuite) benchmarkSkipListInsert(n int, sequential bool) BenchmarkResult {
+	data := generateRandomData(n)
+	operation := "Insert Random"
+	if sequential {
+		data = generateSequentialData(n)
+		operation = "Insert Sequential"
+	}
+	list := ds.NewSkipList()
+	list.SetSeed(1)
+
+	memBefore := getMemoryUsage()
+	start := time.Now()
+
+	for _, v := range data {
+		list.Insert(v)
+	}
+
+	duration := time.Since(start)
+	memAfter := getMemoryUsage()
+
+	return BenchmarkResult{
+		Operation:     operation,
+		DataStructure: "SkipList",
+		NumElements:   n,
+		Duration:      duration,
+		OpsPerSecond:  float64(n) / duration.Seconds(),
+		MemoryUsed:    calcMemoryDiff(memBefore, memAfter),
+	}
+}
+
 func (bs *BenchmarkSuite) benchmarkAVLFind(n int) BenchmarkResult {
 	tree := ds.NewAVLTree()
 	for i := 0; i < n; i++ {
@@ -854,96 +1560,157 @@ func (bs *BenchmarkSuite) benchmarkAVLRemove(n int) BenchmarkResult {
 }
 
 // ============================================================================
-// SERIALIZATION BENCHMARKS
+// BALANCED TREE BENCHMARKS
 // ============================================================================
 
-func (bs *BenchmarkSuite) benchmarkSerializationBinary(n int) []BenchmarkResult {
-	results := make([]BenchmarkResult, 0)
-	tmpDir := os.TempDir()
+// balancedTree is what the rebalancing comparison needs from AVLTree and
+// RedBlackTree.
+type balancedTree interface {
+	Insert(key int)
+	Remove(key int)
+	Stats() ds.TreeStats
+	ResetStats()
+}
 
-	// Array
-	arr := ds.NewMyArray()
-	for i := 0; i < n; i++ {
-		arr.AddToEnd(i)
+var balancedTrees = []struct {
+	name string
+	new  func() balancedTree
+}{
+	{"AVLTree", func() balancedTree { return ds.NewAVLTree() }},
+	{"RedBlackTree", func() balancedTree { return ds.NewRedBlackTree() }},
+}
+
+// treeWorkRow holds the rebalancing work of one timed phase.
+type treeWorkRow struct {
+	tree      string
+	operation string
+	n         int
+	stats     ds.TreeStats
+}
+
+// benchmarkTreeWork times inserting data into tree. With remove set it
+// inserts data untimed first and times removing every other key instead.
+func (bs *BenchmarkSuite) benchmarkTreeWork(name, operation string, tree balancedTree, data []int, remove bool) (BenchmarkResult, treeWorkRow) {
+	ops := len(data)
+	if remove {
+		for _, v := range data {
+			tree.Insert(v)
+		}
+		ops = (len(data) + 1) / 2
 	}
-	filename := tmpDir + "/array_bench.bin"
+	tree.ResetStats()
+
 	start := time.Now()
-	arr.Serialize(filename)
-	serDur := time.Since(start)
-	start = time.Now()
-	arr.Deserialize(filename)
-	desDur := time.Since(start)
-	os.Remove(filename)
-	results = append(results, BenchmarkResult{
-		Operation:     "Binary Serialize",
-		DataStructure: "MyArray",
-		NumElements:   n,
-		Duration:      serDur,
-		OpsPerSecond:  float64(n) / serDur.Seconds(),
-	})
-	results = append(results, BenchmarkResult{
-		Operation:     "Binary Deserialize",
-		DataStructure: "MyArray",
-		NumElements:   n,
-		Duration:      desDur,
-		OpsPerSecond:  float64(n) / desDur.Seconds(),
-	})
+	if remove {
+		for i := 0; i < len(data); i += 2 {
+			tree.Remove(data[i])
+		}
+	} else {
+		for _, v := range data {
+			tree.Insert(v)
+		}
+	}
+	duration := time.Since(start)
 
-	// AVL Tree
-	tree := ds.NewAVLTree()
-	for i := 0; i < n; i++ {
-		tree.Insert(i)
+	return BenchmarkResult{
+		Operation:     operation,
+		DataStructure: name,
+		NumElements:   ops,
+		Duration:      duration,
+		OpsPerSecond:  float64(ops) / duration.Seconds(),
+		MemoryUsed:    0,
+	}, treeWorkRow{
+		tree:      name,
+		operation: operation,
+		n:         ops,
+		stats:     tree.Stats(),
 	}
-	filename = tmpDir + "/avl_bench.bin"
-	start = time.Now()
-	tree.Serialize(filename)
-	serDur = time.Since(start)
-	start = time.Now()
-	tree.Deserialize(filename)
-	desDur = time.Since(start)
-	os.Remove(filename)
-	results = append(results, BenchmarkResult{
-		Operation:     "Binary Serialize",
-		DataStructure: "AVLTree",
-		NumElements:   n,
-		Duration:      serDur,
-		OpsPerSecond:  float64(n) / serDur.Seconds(),
-	})
-	results = append(results, BenchmarkResult{
-		Operation:     "Binary Deserialize",
-		DataStructure: "AVLTree",
-		NumElements:   n,
-		Duration:      desDur,
-		OpsPerSecond:  float64(n) / desDur.Seconds(),
-	})
+}
+
+// ============================================================================
+// SERIALIZATION BENCHMARKS
+// ============================================================================
+
+// namedContainer pairs a structure with the name used in result tables
+type namedContainer struct {
+	name string
+	c    ds.Container[int]
+}
 
-	// Hash Table Chain
+// filledContainers returns every data structure holding n elements
+func filledContainers(n int) []namedContainer {
+	arr := ds.NewMyArray()
+	sll := ds.NewSinglyLinkedList()
+	dll := ds.NewDoublyLinkedList()
+	stack := ds.NewMyStack()
+	queue := ds.NewMyQueue()
 	htc := ds.NewHashTableChain(n / 4)
+	hto := ds.NewHashTableOpen(n / 4)
+	htk := ds.NewHashTableCuckoo(n / 4)
+	tree := ds.NewAVLTree()
 	for i := 0; i < n; i++ {
+		arr.AddToEnd(i)
+		sll.PushBack(i)
+		dll.PushBack(i)
+		stack.Push(i)
+		queue.Push(i)
 		htc.Insert(i, i*2)
+		hto.Insert(i, i*2)
+		htk.Insert(i, i*2)
+		tree.Insert(i)
+	}
+
+	return []namedContainer{
+		{"MyArray", arr},
+		{"SinglyLinkedList", sll},
+		{"DoublyLinkedList", dll},
+		{"Stack", stack},
+		{"Queue", queue},
+		{"HashTableChain", htc},
+		{"HashTableOpen", hto},
+		{"HashTableCuckoo", htk},
+		{"AVLTree", tree},
+	}
+}
+
+func (bs *BenchmarkSuite) benchmarkSerializationBinary(n int) []BenchmarkResult {
+	results := make([]BenchmarkResult, 0)
+	filename := os.TempDir() + "/container_bench.bin"
+
+	for _, nc := range filledContainers(n) {
+		file, err := os.Create(filename)
+		if err != nil {
+			fmt.Printf("   Skipping %s: %v\n", nc.name, err)
+			continue
+		}
+
+		start := time.Now()
+		nc.c.SerializeTo(file)
+		serDur := time.Since(start)
+
+		file.Seek(0, io.SeekStart)
+		start = time.Now()
+		nc.c.DeserializeFrom(file)
+		desDur := time.Since(start)
+
+		file.Close()
+		os.Remove(filename)
+
+		results = append(results, BenchmarkResult{
+			Operation:     "Binary Serialize",
+			DataStructure: nc.name,
+			NumElements:   n,
+			Duration:      serDur,
+			OpsPerSecond:  float64(n) / serDur.Seconds(),
+		})
+		results = append(results, BenchmarkResult{
+			Operation:     "Binary Deserialize",
+			DataStructure: nc.name,
+			NumElements:   n,
+			Duration:      desDur,
+			OpsPerSecond:  float64(n) / desDur.Seconds(),
+		})
 	}
-	filename = tmpDir + "/htc_bench.bin"
-	start = time.Now()
-	htc.Serialize(filename)
-	serDur = time.Since(start)
-	start = time.Now()
-	htc.Deserialize(filename)
-	desDur = time.Since(start)
-	os.Remove(filename)
-	results = append(results, BenchmarkResult{
-		Operation:     "Binary Serialize",
-		DataStructure: "HashTableChain",
-		NumElements:   n,
-		Duration:      serDur,
-		OpsPerSecond:  float64(n) / serDur.Seconds(),
-	})
-	results = append(results, BenchmarkResult{
-		Operation:     "Binary Deserialize",
-		DataStructure: "HashTableChain",
-		NumElements:   n,
-		Duration:      desDur,
-		OpsPerSecond:  float64(n) / desDur.Seconds(),
-	})
 
 	return results
 }
@@ -1030,10 +1797,15 @@ func printMenu() {
 	fmt.Println("│  1.  MyArray                      5.  Queue                                  │")
 	fmt.Println("│  2.  Singly Linked List           6.  Hash Table (Chaining)                  │")
 	fmt.Println("│  3.  Doubly Linked List           7.  Hash Table (Open Addressing)           │")
-	fmt.Println("│  4.  Stack                        8.  AVL Tree                               │")
+	fmt.Println("│  4.  Stack                        8.  AVL Tree vs Skip List                  │")
 	fmt.Println("├──────────────────────────────────────────────────────────────────────────────┤")
 	fmt.Println("│  9.  Run ALL Benchmarks          10.  Serialization Comparison               │")
 	fmt.Println("│ 11.  Compare Similar Operations  12.  Custom Size Benchmark                  │")
+	fmt.Println("│ 13.  Probing Strategies          14.  Hash Table (Cuckoo)                    │")
+	fmt.Println("│ 15.  Concurrent Map (parallel)   16.  Resize Latency (worst insert)          │")
+	fmt.Println("│ 17.  Blocking Queue vs channel   18.  Lock-free Queue (contended)            │")
+	fmt.Println("│ 19.  Priority Queue vs container/heap                                        │")
+	fmt.Println("│ 20.  Cache Hit Ratio (LRU/LFU/ARC) 21.  AVL vs Red-Black (rotations)         │")
 	fmt.Println("├──────────────────────────────────────────────────────────────────────────────┤")
 	fmt.Println("│  0.  Exit                                                                    │")
 	fmt.Println("└──────────────────────────────────────────────────────────────────────────────┘")
@@ -1103,6 +1875,39 @@ func printComparisonTable(results []BenchmarkResult, operation string) {
 	fmt.Println("╚══════════════════════════════════════════════════════════════════════════════╝")
 }
 
+func printHitRatios(rows []hitRatioRow) {
+	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║  %-75s ║\n", "HIT RATIO (Get hits / lookups)")
+	fmt.Println("╠══════════════════════════════════════════════════════════════════════════════╣")
+	header := fmt.Sprintf("%-22s │ %8s", "Trace", "Capacity")
+	for _, policy := range cachePolicies {
+		header += fmt.Sprintf(" │ %9s", policy.name)
+	}
+	fmt.Printf("║  %-75s ║\n", header)
+	fmt.Println("╟──────────────────────────────────────────────────────────────────────────────╢")
+	for _, row := range rows {
+		line := fmt.Sprintf("%-22s │ %8d", truncateString(row.trace, 22), row.capacity)
+		for _, ratio := range row.ratios {
+			line += fmt.Sprintf(" │ %8.2f%%", 100*ratio)
+		}
+		fmt.Printf("║  %-75s ║\n", line)
+	}
+	fmt.Println("╚══════════════════════════════════════════════════════════════════════════════╝")
+}
+
+func printTreeWork(rows []treeWorkRow) {
+	fmt.Printf("\n╔══════════════════════════════════════════════════════════════════════════════╗\n")
+	fmt.Printf("║  %-75s ║\n", "REBALANCING WORK")
+	fmt.Println("╠══════════════════════════════════════════════════════════════════════════════╣")
+	fmt.Printf("║  %-14s │ %-17s │ %8s │ %10s │ %12s    ║\n", "Tree", "Operation", "Ops", "Rotations", "Recolorings")
+	fmt.Println("╟──────────────────────────────────────────────────────────────────────────────╢")
+	for _, row := range rows {
+		fmt.Printf("║  %-14s │ %-17s │ %8d │ %10d │ %12d    ║\n",
+			row.tree, row.operation, row.n, row.stats.Rotations, row.stats.Recolorings)
+	}
+	fmt.Println("╚══════════════════════════════════════════════════════════════════════════════╝")
+}
+
 // ============================================================================
 // MAIN BENCHMARK RUNNERS
 // ============================================================================
@@ -1169,9 +1974,12 @@ func (bs *BenchmarkSuite) runStackBenchmarks() []BenchmarkResult {
 
 	for _, size := range bs.sizes {
 		fmt.Printf("   Testing with %d elements...\n", size)
-		results = append(results, bs.benchmarkStackPush(size))
-		results = append(results, bs.benchmarkStackPop(size))
-		results = append(results, bs.benchmarkStackPeek(size))
+		results = append(results, bs.benchmarkStackPush(size, false))
+		results = append(results, bs.benchmarkStackPush(size, true))
+		results = append(results, bs.benchmarkStackPop(size, false))
+		results = append(results, bs.benchmarkStackPop(size, true))
+		results = append(results, bs.benchmarkStackPeek(size, false))
+		results = append(results, bs.benchmarkStackPeek(size, true))
 	}
 
 	return results
@@ -1183,9 +1991,12 @@ func (bs *BenchmarkSuite) runQueueBenchmarks() []BenchmarkResult {
 
 	for _, size := range bs.sizes {
 		fmt.Printf("   Testing with %d elements...\n", size)
-		results = append(results, bs.benchmarkQueuePush(size))
-		results = append(results, bs.benchmarkQueuePop(size))
-		results = append(results, bs.benchmarkQueuePeek(size))
+		results = append(results, bs.benchmarkQueuePush(size, false))
+		results = append(results, bs.benchmarkQueuePush(size, true))
+		results = append(results, bs.benchmarkQueuePop(size, false))
+		results = append(results, bs.benchmarkQueuePop(size, true))
+		results = append(results, bs.benchmarkQueuePeek(size, false))
+		results = append(results, bs.benchmarkQueuePeek(size, true))
 	}
 
 	return results
@@ -1214,19 +2025,207 @@ func (bs *BenchmarkSuite) runHashOpenBenchmarks() []BenchmarkResult {
 		results = append(results, bs.benchmarkHashOpenInsert(size))
 		results = append(results, bs.benchmarkHashOpenGet(size))
 		results = append(results, bs.benchmarkHashOpenRemove(size))
+
+		groups := ds.HashTableOpenOptions{Probing: ds.GroupProbing}
+		results = append(results, bs.benchmarkHashOpenInsert(size, groups))
+		results = append(results, bs.benchmarkHashOpenGet(size, groups))
+		results = append(results, bs.benchmarkHashOpenRemove(size, groups))
+	}
+
+	return results
+}
+
+func (bs *BenchmarkSuite) runHashCuckooBenchmarks() []BenchmarkResult {
+	results := make([]BenchmarkResult, 0)
+	fmt.Println("\n🔄 Running Hash Table (Cuckoo) benchmarks...")
+
+	for _, size := range bs.sizes {
+		fmt.Printf("   Testing with %d elements...\n", size)
+		results = append(results, bs.benchmarkHashCuckooInsert(size))
+		results = append(results, bs.benchmarkHashCuckooGet(size))
+		results = append(results, bs.benchmarkHashCuckooRemove(size))
+	}
+
+	return results
+}
+
+func (bs *BenchmarkSuite) runConcurrentMapBenchmarks() []BenchmarkResult {
+	results := make([]BenchmarkResult, 0)
+	fmt.Printf("\n🔄 Running ConcurrentMap benchmarks (GOMAXPROCS=%d)...\n", runtime.GOMAXPROCS(0))
+
+	for _, size := range bs.sizes {
+		fmt.Printf("   Testing with %d elements...\n", size)
+		for _, goroutines := range []int{1, 2, 4, 8} {
+			locked := &lockedChain{table: ds.NewHashTableChain(size / 4)}
+			results = append(results, bs.benchmarkParallelMixed("Mutex+Chain", locked, size, goroutines))
+			results = append(results, bs.benchmarkParallelMixed("ConcurrentMap", ds.NewConcurrentMap(0), size, goroutines))
+		}
+	}
+
+	return results
+}
+
+func (bs *BenchmarkSuite) runResizeLatencyBenchmarks() []BenchmarkResult {
+	results := make([]BenchmarkResult, 0)
+	fmt.Println("\n🔄 Running resize latency benchmarks...")
+
+	for _, size := range []int{10000, 100000, 1000000} {
+		fmt.Printf("   Testing with %d elements...\n", size)
+		open := ds.NewHashTableOpen(16)
+		results = append(results, bs.benchmarkWorstInsert("HashTableOpen", open.Insert, size))
+		openInc := ds.NewHashTableOpen(16, ds.HashTableOpenOptions{IncrementalRehash: true})
+		results = append(results, bs.benchmarkWorstInsert("Open/Incremental", openInc.Insert, size))
+
+		chain := ds.NewHashTableChain(16)
+		results = append(results, bs.benchmarkWorstInsert("HashTableChain", chain.Put, size))
+		chainInc := ds.NewHashTableChain(16)
+		chainInc.SetIncrementalRehash(true)
+		results = append(results, bs.benchmarkWorstInsert("Chain/Incremental", chainInc.Put, size))
+	}
+
+	return results
+}
+
+func (bs *BenchmarkSuite) runBlockingQueueBenchmarks() []BenchmarkResult {
+	results := make([]BenchmarkResult, 0)
+	fmt.Println("\n🔄 Running BlockingQueue benchmarks...")
+
+	const capacity = 64
+	ctx := context.Background()
+	for _, size := range bs.sizes {
+		fmt.Printf("   Testing with %d elements...\n", size)
+		for _, workers := range []int{1, 4} {
+			q := ds.NewBlockingQueue(capacity)
+			results = append(results, bs.benchmarkPipeline("BlockingQueue",
+				func(v int) { q.Put(ctx, v) },
+				func() bool { _, err := q.Take(ctx); return err == nil },
+				q.Close, size, workers))
+
+			ch := make(chan int, capacity)
+			results = append(results, bs.benchmarkPipeline("chan int",
+				func(v int) { ch <- v },
+				func() bool { _, ok := <-ch; return ok },
+				func() { close(ch) }, size, workers))
+		}
+	}
+
+	return results
+}
+
+func (bs *BenchmarkSuite) runLockFreeQueueBenchmarks() []BenchmarkResult {
+	results := make([]BenchmarkResult, 0)
+	fmt.Printf("\n🔄 Running contended queue benchmarks (GOMAXPROCS=%d)...\n", runtime.GOMAXPROCS(0))
+
+	for _, size := range bs.sizes {
+		fmt.Printf("   Testing with %d elements...\n", size)
+		for _, goroutines := range []int{1, 2, 4, 8} {
+			locked := &lockedQueue{queue: ds.NewMyQueue()}
+			results = append(results, bs.benchmarkContendedQueue("Mutex+MyQueue", locked, size, goroutines))
+			results = append(results, bs.benchmarkContendedQueue("LockFreeQueue", ds.NewLockFreeQueue(), size, goroutines))
+		}
+	}
+
+	return results
+}
+
+func (bs *BenchmarkSuite) runPriorityQueueBenchmarks() []BenchmarkResult {
+	results := make([]BenchmarkResult, 0)
+	fmt.Println("\n🔄 Running PriorityQueue benchmarks...")
+
+	for _, size := range bs.sizes {
+		fmt.Printf("   Testing with %d elements...\n", size)
+		for _, useHeapPkg := range []bool{false, true} {
+			results = append(results, bs.benchmarkPQPushPop(size, useHeapPkg))
+			results = append(results, bs.benchmarkPQHeapify(size, useHeapPkg))
+			results = append(results, bs.benchmarkPQUpdate(size, useHeapPkg))
+		}
 	}
 
 	return results
 }
 
+// runBalancedTreeBenchmarks compares the time and the rebalancing work of
+// AVLTree and RedBlackTree.
+func (bs *BenchmarkSuite) runBalancedTreeBenchmarks() ([]BenchmarkResult, []treeWorkRow) {
+	results := make([]BenchmarkResult, 0)
+	rows := make([]treeWorkRow, 0)
+	fmt.Println("\n🔄 Running AVL and red-black tree benchmarks...")
+
+	for _, size := range bs.sizes {
+		fmt.Printf("   Testing with %d elements...\n", size)
+		random := generateRandomData(size)
+		sequential := generateSequentialData(size)
+		for _, tree := range balancedTrees {
+			for _, run := range []struct {
+				operation string
+				data      []int
+				remove    bool
+			}{
+				{"Insert Random", random, false},
+				{"Insert Sequential", sequential, false},
+				{"Remove Random", random, true},
+			} {
+				result, row := bs.benchmarkTreeWork(tree.name, run.operation, tree.new(), run.data, run.remove)
+				results = append(results, result)
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	return results, rows
+}
+
+// runCacheBenchmarks replays the synthetic traces, or the keys in
+// traceFile if it is not empty, against every cache policy.
+func (bs *BenchmarkSuite) runCacheBenchmarks(traceFile string) ([]BenchmarkResult, []hitRatioRow) {
+	results := make([]BenchmarkResult, 0)
+	rows := make([]hitRatioRow, 0)
+	fmt.Println("\n🔄 Running cache hit ratio benchmarks...")
+
+	var fileTrace *cacheTrace
+	if traceFile != "" {
+		trace, err := loadCacheTrace(traceFile)
+		if err != nil {
+			fmt.Printf("   Cannot load trace (%v), using synthetic traces.\n", err)
+		} else {
+			fileTrace = &trace
+		}
+	}
+
+	const requests = 200000
+	for _, capacity := range []int{100, 1000} {
+		fmt.Printf("   Testing with capacity %d...\n", capacity)
+		traces := []cacheTrace{}
+		if fileTrace != nil {
+			traces = append(traces, *fileTrace)
+		} else {
+			traces = generateCacheTraces(requests, capacity)
+		}
+
+		for _, trace := range traces {
+			row := hitRatioRow{trace: trace.name, capacity: capacity}
+			for _, policy := range cachePolicies {
+				result, stats := bs.benchmarkCacheTrace(policy.name, policy.new(capacity), trace)
+				results = append(results, result)
+				row.ratios = append(row.ratios, stats.HitRate())
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return results, rows
+}
+
 func (bs *BenchmarkSuite) runAVLBenchmarks() []BenchmarkResult {
 	results := make([]BenchmarkResult, 0)
-	fmt.Println("\n🔄 Running AVL Tree benchmarks...")
+	fmt.Println("\n🔄 Running AVL Tree and Skip List benchmarks...")
 
 	for _, size := range bs.sizes {
 		fmt.Printf("   Testing with %d elements...\n", size)
 		results = append(results, bs.benchmarkAVLInsertRandom(size))
+		results = append(results, bs.benchmarkSkipListInsert(size, false))
 		results = append(results, bs.benchmarkAVLInsertSequential(size))
+		results = append(results, bs.benchmarkSkipListInsert(size, true))
 		results = append(results, bs.benchmarkAVLFind(size))
 		results = append(results, bs.benchmarkAVLRemove(size))
 	}
@@ -1234,7 +2233,10 @@ func (bs *BenchmarkSuite) runAVLBenchmarks() []BenchmarkResult {
 	return results
 }
 
-func (bs *BenchmarkSuite) runAllBenchmarks() []BenchmarkResult {
+// runAllBenchmarks runs the per-structure benchmarks of menu items 1-8, the
+// cuckoo table of item 14 and the balanced tree comparison of item 21,
+// whose rebalancing work is returned as well.
+func (bs *BenchmarkSuite) runAllBenchmarks() ([]BenchmarkResult, []treeWorkRow) {
 	results := make([]BenchmarkResult, 0)
 	results = append(results, bs.runArrayBenchmarks()...)
 	results = append(results, bs.runSLLBenchmarks()...)
@@ -1243,8 +2245,11 @@ func (bs *BenchmarkSuite) runAllBenchmarks() []BenchmarkResult {
 	results = append(results, bs.runQueueBenchmarks()...)
 	results = append(results, bs.runHashChainBenchmarks()...)
 	results = append(results, bs.runHashOpenBenchmarks()...)
+	results = append(results, bs.runHashCuckooBenchmarks()...)
 	results = append(results, bs.runAVLBenchmarks()...)
-	return results
+	treeResults, treeRows := bs.runBalancedTreeBenchmarks()
+	results = append(results, treeResults...)
+	return results, treeRows
 }
 
 func (bs *BenchmarkSuite) runSerializationComparison() []BenchmarkResult {
@@ -1272,21 +2277,37 @@ func (bs *BenchmarkSuite) runSimilarOperationsComparison() []BenchmarkResult {
 	results = append(results, bs.benchmarkArrayInsertEnd(size))
 	results = append(results, bs.benchmarkSLLPushBack(size))
 	results = append(results, bs.benchmarkDLLPushBack(size))
-	results = append(results, bs.benchmarkStackPush(size))
-	results = append(results, bs.benchmarkQueuePush(size))
+	results = append(results, bs.benchmarkStackPush(size, false))
+	results = append(results, bs.benchmarkQueuePush(size, false))
 	results = append(results, bs.benchmarkHashChainInsert(size))
 	results = append(results, bs.benchmarkHashOpenInsert(size))
+	results = append(results, bs.benchmarkHashCuckooInsert(size))
 	results = append(results, bs.benchmarkAVLInsertRandom(size))
 
 	return results
 }
 
-func (bs *BenchmarkSuite) runCustomSizeBenchmark(size int) []BenchmarkResult {
+func (bs *BenchmarkSuite) runProbingComparison() []BenchmarkResult {
+	results := make([]BenchmarkResult, 0)
+	fmt.Println("\n🔄 Comparing open addressing probing strategies on clustered keys...")
+
+	strategies := []ds.ProbeStrategy{ds.LinearProbing, ds.QuadraticProbing, ds.DoubleHashing, ds.GroupProbing}
+	for _, size := range bs.sizes {
+		fmt.Printf("   Testing with %d elements...\n", size)
+		for _, strategy := range strategies {
+			results = append(results, bs.benchmarkHashOpenProbing(size, strategy)...)
+		}
+	}
+
+	return results
+}
+
+func (bs *BenchmarkSuite) runCustomSizeBenchmark(size int) ([]BenchmarkResult, []treeWorkRow) {
 	oldSizes := bs.sizes
 	bs.sizes = []int{size}
-	results := bs.runAllBenchmarks()
+	results, treeRows := bs.runAllBenchmarks()
 	bs.sizes = oldSizes
-	return results
+	return results, treeRows
 }
 
 // ============================================================================
@@ -1312,6 +2333,7 @@ func main() {
 		}
 
 		var results []BenchmarkResult
+		var treeRows []treeWorkRow
 
 		switch choice {
 		case 0:
@@ -1334,7 +2356,7 @@ func main() {
 		case 8:
 			results = bs.runAVLBenchmarks()
 		case 9:
-			results = bs.runAllBenchmarks()
+			results, treeRows = bs.runAllBenchmarks()
 		case 10:
 			results = bs.runSerializationComparison()
 		case 11:
@@ -1353,7 +2375,41 @@ func main() {
 				fmt.Println("Invalid size. Using default 10000.")
 				customSize = 10000
 			}
-			results = bs.runCustomSizeBenchmark(customSize)
+			results, treeRows = bs.runCustomSizeBenchmark(customSize)
+		case 13:
+			results = bs.runProbingComparison()
+			printResults(results)
+			printComparisonTable(results, "insert clustered")
+			printComparisonTable(results, "get clustered")
+			bs.results = append(bs.results, results...)
+			fmt.Println("\nPress Enter to continue...")
+			reader.ReadString('\n')
+			continue
+		case 14:
+			results = bs.runHashCuckooBenchmarks()
+		case 15:
+			results = bs.runConcurrentMapBenchmarks()
+		case 16:
+			results = bs.runResizeLatencyBenchmarks()
+		case 17:
+			results = bs.runBlockingQueueBenchmarks()
+		case 18:
+			results = bs.runLockFreeQueueBenchmarks()
+		case 19:
+			results = bs.runPriorityQueueBenchmarks()
+		case 20:
+			fmt.Print("Trace file (one key per line, Enter for synthetic traces): ")
+			traceFile, _ := reader.ReadString('\n')
+			var rows []hitRatioRow
+			results, rows = bs.runCacheBenchmarks(strings.TrimSpace(traceFile))
+			printResults(results)
+			printHitRatios(rows)
+			bs.results = append(bs.results, results...)
+			fmt.Println("\nPress Enter to continue...")
+			reader.ReadString('\n')
+			continue
+		case 21:
+			results, treeRows = bs.runBalancedTreeBenchmarks()
 		default:
 			fmt.Println("Invalid choice. Please try again.")
 			continue
@@ -1363,6 +2419,9 @@ func main() {
 			printResults(results)
 			bs.results = append(bs.results, results...)
 		}
+		if len(treeRows) > 0 {
+			printTreeWork(treeRows)
+		}
 
 		fmt.Println("\nPress Enter to continue...")
 		reader.ReadString('\n')
@@ -0,0 +1,280 @@
+package datastructures
+
+import (
+	"fmt"
+	"iter"
+)
+
+type arcEntry[K, V any] struct {
+	key   K
+	value V      // zero while the key is a ghost
+	node  *DNode // node in t1, t2, b1 or b2; its list says which
+}
+
+// ARCCache is the Adaptive Replacement Cache of Megiddo and Modha. Its
+// entries are split between t1, keys used once recently, and t2, keys
+// used at least twice. The keys last evicted from each are remembered
+// without their values in the ghost lists b1 and b2. A Put of a key found
+// in b1 shows that t1 was too small and raises p, the target length of
+// t1; one found in b2 lowers it. The cache so tunes itself between
+// recency and frequency, and a scan of new keys can only flush t1.
+type ARCCache[K, V any] struct {
+	capacity int
+	p        int
+	t1, t2   *DoublyLinkedList // resident slots, most recent first
+	b1, b2   *DoublyLinkedList // ghost slots, most recent first
+	index    *HashTableChain[K, int]
+	slots    slotPool[arcEntry[K, V]]
+	onEvict  func(key K, value V)
+	stats    CacheStats
+	version  int
+}
+
+// NewARCCache returns a cache of int keys and values.
+func NewARCCache(capacity int) *ARCCache[int, int] {
+	return NewARCCacheOf[int, int](capacity, IntHasher, nil)
+}
+
+// NewARCCacheOf builds a cache for any key type, like NewLRUCacheOf. The
+// index also holds up to capacity ghost keys.
+func NewARCCacheOf[K, V any](capacity int, hasher Hasher[K], equal func(a, b K) bool) *ARCCache[K, V] {
+	if capacity <= 0 {
+		panic("datastructures: ARCCache capacity must be positive")
+	}
+	return &ARCCache[K, V]{
+		capacity: capacity,
+		t1:       NewDoublyLinkedList(),
+		t2:       NewDoublyLinkedList(),
+		b1:       NewDoublyLinkedList(),
+		b2:       NewDoublyLinkedList(),
+		index:    NewHashTableChainOf[K, int](2*capacity, hasher, equal),
+	}
+}
+
+// SetOnEvict registers fn to be called with every entry the cache evicts
+// to make room. Entries removed by Remove or Clear are not reported.
+func (c *ARCCache[K, V]) SetOnEvict(fn func(key K, value V)) {
+	c.onEvict = fn
+}
+
+// resident returns the slot of key if its value is cached.
+func (c *ARCCache[K, V]) resident(key K) (int, bool) {
+	slot, ok := c.index.Get(key)
+	if !ok {
+		return 0, false
+	}
+	list := c.slots.at(slot).node.list
+	return slot, list == c.t1 || list == c.t2
+}
+
+// moveTo puts the entry in slot at the front of list.
+func (c *ARCCache[K, V]) moveTo(slot int, list *DoublyLinkedList) {
+	entry := c.slots.at(slot)
+	entry.node.list.RemoveNode(entry.node)
+	entry.node = list.PushFront(slot)
+}
+
+// Get returns the value for key and moves it to the front of t2. A ghost
+// key is a miss.
+func (c *ARCCache[K, V]) Get(key K) (V, bool) {
+	slot, ok := c.resident(key)
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.moveTo(slot, c.t2)
+	c.version++
+	return c.slots.at(slot).value, true
+}
+
+// Peek returns the value for key without moving it or counting a lookup.
+func (c *ARCCache[K, V]) Peek(key K) (V, bool) {
+	slot, ok := c.resident(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return c.slots.at(slot).value, true
+}
+
+func (c *ARCCache[K, V]) Contains(key K) bool {
+	_, ok := c.resident(key)
+	return ok
+}
+
+// Put stores value under key. A cached or ghost key goes to the front of
+// t2, a new key to the front of t1.
+func (c *ARCCache[K, V]) Put(key K, value V) {
+	c.version++
+	if slot, ok := c.index.Get(key); ok {
+		switch c.slots.at(slot).node.list {
+		case c.b1:
+			c.p = min(c.capacity, c.p+max(c.b2.Len()/c.b1.Len(), 1))
+			c.replace(false)
+		case c.b2:
+			c.p = max(0, c.p-max(c.b1.Len()/c.b2.Len(), 1))
+			c.replace(true)
+		}
+		c.slots.at(slot).value = value
+		c.moveTo(slot, c.t2)
+		return
+	}
+
+	total := c.t1.Len() + c.t2.Len() + c.b1.Len() + c.b2.Len()
+	switch {
+	case c.t1.Len()+c.b1.Len() >= c.capacity:
+		if c.t1.Len() < c.capacity {
+			c.dropGhost(c.b1)
+			c.replace(false)
+		} else {
+			c.evict(c.t1, nil)
+		}
+	case total >= c.capacity:
+		if total >= 2*c.capacity {
+			c.dropGhost(c.b2)
+		}
+		c.replace(false)
+	}
+
+	slot := c.slots.alloc(arcEntry[K, V]{key: key, value: value})
+	c.slots.at(slot).node = c.t1.PushFront(slot)
+	c.index.Put(key, slot)
+}
+
+// replace makes room for one entry if the cache is full, evicting from t1
+// when it is longer than its target p and from t2 otherwise. inB2 breaks
+// the tie at p in favour of t2 when the key being added was a b2 ghost.
+func (c *ARCCache[K, V]) replace(inB2 bool) {
+	t1 := c.t1.Len()
+	if t1+c.t2.Len() < c.capacity {
+		return
+	}
+	if t1 > 0 && (t1 > c.p || (inB2 && t1 == c.p) || c.t2.IsEmpty()) {
+		c.evict(c.t1, c.b1)
+	} else {
+		c.evict(c.t2, c.b2)
+	}
+}
+
+// evict drops the value at the back of from and keeps its key in ghost,
+// or forgets the key as well if ghost is nil.
+func (c *ARCCache[K, V]) evict(from, ghost *DoublyLinkedList) {
+	slot := from.Back().Value()
+	entry := c.slots.at(slot)
+	key, value := entry.key, entry.value
+	if ghost != nil {
+		var zero V
+		entry.value = zero
+		c.moveTo(slot, ghost)
+	} else {
+		c.removeSlot(slot)
+	}
+	c.stats.Evictions++
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
+
+// dropGhost forgets the oldest key of a ghost list.
+func (c *ARCCache[K, V]) dropGhost(ghost *DoublyLinkedList) {
+	c.removeSlot(ghost.Back().Value())
+}
+
+func (c *ARCCache[K, V]) removeSlot(slot int) {
+	entry := c.slots.at(slot)
+	entry.node.list.RemoveNode(entry.node)
+	c.index.Remove(entry.key)
+	c.slots.release(slot)
+}
+
+// Remove deletes key and reports whether its value was cached. A ghost
+// key is left alone.
+func (c *ARCCache[K, V]) Remove(key K) bool {
+	slot, ok := c.resident(key)
+	if !ok {
+		return false
+	}
+	c.removeSlot(slot)
+	c.version++
+	return true
+}
+
+// Len returns the number of cached values; ghosts are not counted.
+func (c *ARCCache[K, V]) Len() int {
+	return c.t1.Len() + c.t2.Len()
+}
+
+func (c *ARCCache[K, V]) Cap() int {
+	return c.capacity
+}
+
+func (c *ARCCache[K, V]) IsEmpty() bool {
+	return c.Len() == 0
+}
+
+// Target returns p, the length t1 is currently steered towards. It grows
+// on b1 ghost hits, which favour recency, and shrinks on b2 ghost hits.
+func (c *ARCCache[K, V]) Target() int {
+	return c.p
+}
+
+// Clear drops every entry and ghost without calling onEvict and keeps the
+// statistics.
+func (c *ARCCache[K, V]) Clear() {
+	for _, list := range []*DoublyLinkedList{c.t1, c.t2, c.b1, c.b2} {
+		list.Clear()
+	}
+	c.index.Clear()
+	c.slots.reset()
+	c.p = 0
+	c.version++
+}
+
+func (c *ARCCache[K, V]) Stats() CacheStats {
+	return c.stats
+}
+
+func (c *ARCCache[K, V]) ResetStats() {
+	c.stats = CacheStats{}
+}
+
+// Keys returns the cached keys: those of t2, then those of t1, each from
+// most to least recently used.
+func (c *ARCCache[K, V]) Keys() []K {
+	keys := make([]K, 0, c.Len())
+	for key := range c.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// All yields the cached entries in the order of Keys without moving them.
+func (c *ARCCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		version := c.version
+		for _, list := range []*DoublyLinkedList{c.t2, c.t1} {
+			for n := list.Front(); n != nil; n = n.Next() {
+				entry := c.slots.at(n.Value())
+				if !yield(entry.key, entry.value) {
+					return
+				}
+				checkVersion(version, c.version)
+			}
+		}
+	}
+}
+
+func (c *ARCCache[K, V]) Print() {
+	fmt.Printf("ARCCache (%d/%d, p=%d) [", c.Len(), c.capacity, c.p)
+	first := true
+	for key, value := range c.All() {
+		if !first {
+			fmt.Print(", ")
+		}
+		first = false
+		fmt.Printf("%v:%v", key, value)
+	}
+	fmt.Println("]")
+}
@@ -5,39 +5,49 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 )
 
-type MyArray struct {
-	data     []int
+type MyArray[T any] struct {
+	data     []T
 	capacity int
 	size     int
+	version  int
 }
 
-func NewMyArray() *MyArray {
-	return &MyArray{
-		data:     make([]int, 2),
+// NewMyArray returns the int array the rest of the package was written
+// against. Use NewMyArrayOf for other element types.
+func NewMyArray() *MyArray[int] {
+	return NewMyArrayOf[int]()
+}
+
+func NewMyArrayOf[T any]() *MyArray[T] {
+	return &MyArray[T]{
+		data:     make([]T, 2),
 		capacity: 2,
 		size:     0,
 	}
 }
 
-func (a *MyArray) resize(newCapacity int) {
-	newData := make([]int, newCapacity)
+func (a *MyArray[T]) resize(newCapacity int) {
+	newData := make([]T, newCapacity)
 	copy(newData, a.data[:a.size])
 	a.data = newData
 	a.capacity = newCapacity
 }
 
-func (a *MyArray) AddToEnd(value int) {
+func (a *MyArray[T]) AddToEnd(value T) {
 	if a.size == a.capacity {
 		a.resize(a.capacity * 2)
 	}
 	a.data[a.size] = value
 	a.size++
+	a.version++
 }
 
-func (a *MyArray) AddAtIndex(index int, value int) error {
+func (a *MyArray[T]) AddAtIndex(index int, value T) error {
 	if index < 0 || index > a.size  {
 		return errors.New("index out of bounds")
 	}
@@ -49,17 +59,19 @@ func (a *MyArray) AddAtIndex(index int, value int) error {
 	}
 	a.data[index] = value
 	a.size++
+	a.version++
 	return nil
 }
 
-func (a *MyArray) GetAtIndex(index int) (int, error) {
+func (a *MyArray[T]) GetAtIndex(index int) (T, error) {
 	if index < 0 || index >= a.size {
-		return 0, errors.New("index out of bounds")
+		var zero T
+		return zero, errors.New("index out of bounds")
 	}
 	return a.data[index], nil
 }
 
-func (a *MyArray) RemoveAtIndex(index int) error {
+func (a *MyArray[T]) RemoveAtIndex(index int) error {
 	if  index < 0 || index >= a.size{
 		return errors.New("index out of bounds")
 	}
@@ -67,10 +79,11 @@ func (a *MyArray) RemoveAtIndex(index int) error {
 		a.data[i] = a.data[i+1]
 	}
 	a.size--
+	a.version++
 	return nil
 }
 
-func (a *MyArray) ReplaceAtIndex(index int, value int) error {
+func (a *MyArray[T]) ReplaceAtIndex(index int, value T) error {
 	if index < 0 || index >= a.size  {
 		return errors.New("index out of bounds")
 	}
@@ -78,11 +91,49 @@ func (a *MyArray) ReplaceAtIndex(index int, value int) error {
 	return nil
 }
 
-func (a *MyArray) GetLength() int {
+func (a *MyArray[T]) GetLength() int {
+	return a.size
+}
+
+func (a *MyArray[T]) Len() int {
 	return a.size
 }
 
-func (a *MyArray) Print() {
+func (a *MyArray[T]) IsEmpty() bool {
+	return a.size == 0
+}
+
+func (a *MyArray[T]) Clear() {
+	a.data = make([]T, 2)
+	a.capacity = 2
+	a.size = 0
+	a.version++
+}
+
+func (a *MyArray[T]) Values() []T {
+	values := make([]T, a.size)
+	copy(values, a.data[:a.size])
+	return values
+}
+
+func (a *MyArray[T]) At(index int) (T, error) {
+	return a.GetAtIndex(index)
+}
+
+// All yields index/value pairs in order.
+func (a *MyArray[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		version := a.version
+		for i := 0; i < a.size; i++ {
+			if !yield(i, a.data[i]) {
+				return
+			}
+			checkVersion(version, a.version)
+		}
+	}
+}
+
+func (a *MyArray[T]) Print() {
 	fmt.Print("Array [")
 	for i := 0; i < a.size; i++ {
 		fmt.Print(a.data[i])
@@ -94,33 +145,41 @@ func (a *MyArray) Print() {
 }
 
 // Binary Serialization
-func (a *MyArray) Serialize(filename string) error {
+func (a *MyArray[T]) Serialize(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for writing: %w", err)
 	}
 	defer file.Close()
 
-	if err := binary.Write(file, binary.LittleEndian, uint64(a.size)); err != nil {
+	return a.SerializeTo(file)
+}
+
+func (a *MyArray[T]) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(a.size)); err != nil {
 		return err
 	}
 	for i := 0; i < a.size; i++ {
-		if err := binary.Write(file, binary.LittleEndian, int32(a.data[i])); err != nil {
+		if err := writeValue(w, a.data[i]); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (a *MyArray) Deserialize(filename string) error {
+func (a *MyArray[T]) Deserialize(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for reading: %w", err)
 	}
 	defer file.Close()
 
+	return a.DeserializeFrom(file)
+}
+
+func (a *MyArray[T]) DeserializeFrom(r io.Reader) error {
 	var newSize uint64
-	if err := binary.Read(file, binary.LittleEndian, &newSize); err != nil {
+	if err := binary.Read(r, binary.LittleEndian, &newSize); err != nil {
 		return err
 	}
 
@@ -128,43 +187,44 @@ func (a *MyArray) Deserialize(filename string) error {
 		a.resize(int(newSize))
 	}
 	a.size = int(newSize)
+	a.version++
 
 	for i := 0; i < a.size; i++ {
-		var value int32
-		if err := binary.Read(file, binary.LittleEndian, &value); err != nil {
+		value, err := readValue[T](r)
+		if err != nil {
 			return err
 		}
-		a.data[i] = int(value)
+		a.data[i] = value
 	}
 	return nil
 }
 
 // JSON Serialization
-type arrayJSON struct {
-	Data []int `json:"data"`
+type arrayJSON[T any] struct {
+	Data []T `json:"data"`
 }
 
-func (a *MyArray) SerializeJSON(filename string) error {
+func (a *MyArray[T]) SerializeJSON(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for writing: %w", err)
 	}
 	defer file.Close()
 
-	data := arrayJSON{Data: a.data[:a.size]}
+	data := arrayJSON[T]{Data: a.data[:a.size]}
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
 
-func (a *MyArray) DeserializeJSON(filename string) error {
+func (a *MyArray[T]) DeserializeJSON(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for reading: %w", err)
 	}
 	defer file.Close()
 
-	var data arrayJSON
+	var data arrayJSON[T]
 	decoder := json.NewDecoder(file)
 	if err := decoder.Decode(&data); err != nil {
 		return err
@@ -175,6 +235,7 @@ func (a *MyArray) DeserializeJSON(filename string) error {
     	a.resize(newSize)         
 	}
 	a.size = newSize              
+	a.version++
 	copy(a.data, data.Data)
 	return nil
 }
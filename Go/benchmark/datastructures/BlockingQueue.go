@@ -0,0 +1,185 @@
+package datastructures
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrQueueClosed is returned by Put once the queue is closed, and by Take
+// once it is closed and empty.
+var ErrQueueClosed = errors.New("queue closed")
+
+// BlockingQueue is a bounded FIFO of ints that is safe for concurrent use.
+// It wraps a MyQueue behind a mutex; Put waits while the queue is full and
+// Take while it is empty, until the context is done.
+type BlockingQueue struct {
+	mu       sync.Mutex
+	items    *MyQueue
+	capacity int
+	closed   bool
+	// changed is closed and replaced whenever an item is added or removed
+	// or the queue is closed, waking every waiter to re-check its
+	// condition. Unlike sync.Cond it can be selected on together with
+	// ctx.Done().
+	changed chan struct{}
+	waiters int // goroutines waiting on changed
+}
+
+// NewBlockingQueue returns a queue holding at most capacity items. A
+// capacity <= 0 makes it unbounded, so Put never blocks.
+func NewBlockingQueue(capacity int) *BlockingQueue {
+	if capacity <= 0 {
+		capacity = math.MaxInt
+	}
+	return &BlockingQueue{
+		items:    NewMyQueue(),
+		capacity: capacity,
+		changed:  make(chan struct{}),
+	}
+}
+
+// signal wakes all waiters. The caller holds q.mu.
+func (q *BlockingQueue) signal() {
+	if q.waiters == 0 {
+		return
+	}
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// wait locks q.mu and returns once ready reports true, with the lock held.
+// If ctx is done first it returns ctx.Err() with the lock released.
+func (q *BlockingQueue) wait(ctx context.Context, ready func() bool) error {
+	q.mu.Lock()
+	for !ready() {
+		changed := q.changed
+		q.waiters++
+		q.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			q.mu.Lock()
+			q.waiters--
+			q.mu.Unlock()
+			return ctx.Err()
+		}
+		q.mu.Lock()
+		q.waiters--
+	}
+	return nil
+}
+
+// Put appends value, waiting while the queue is full. It returns
+// ErrQueueClosed if the queue is or becomes closed, or the context's error
+// if ctx is done first.
+func (q *BlockingQueue) Put(ctx context.Context, value int) error {
+	ready := func() bool { return q.closed || q.items.Len() < q.capacity }
+	if err := q.wait(ctx, ready); err != nil {
+		return err
+	}
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	q.items.Push(value)
+	q.signal()
+	return nil
+}
+
+// Take removes and returns the front item, waiting while the queue is
+// empty. Items put before Close are still handed out; after that Take
+// returns ErrQueueClosed.
+func (q *BlockingQueue) Take(ctx context.Context) (int, error) {
+	ready := func() bool { return q.closed || !q.items.IsEmpty() }
+	if err := q.wait(ctx, ready); err != nil {
+		return 0, err
+	}
+	defer q.mu.Unlock()
+
+	if q.items.IsEmpty() {
+		return 0, ErrQueueClosed
+	}
+	value, _ := q.items.Peek()
+	q.items.Pop()
+	q.signal()
+	return value, nil
+}
+
+// Offer is Put with a timeout instead of a context. It reports whether
+// value was added; a timeout <= 0 only adds it if there is room right now.
+func (q *BlockingQueue) Offer(value int, timeout time.Duration) bool {
+	ctx, cancel := timeoutContext(timeout)
+	defer cancel()
+	return q.Put(ctx, value) == nil
+}
+
+// Poll is Take with a timeout instead of a context. ok is false if no item
+// arrived in time or the queue is closed and empty.
+func (q *BlockingQueue) Poll(timeout time.Duration) (value int, ok bool) {
+	ctx, cancel := timeoutContext(timeout)
+	defer cancel()
+	value, err := q.Take(ctx)
+	return value, err == nil
+}
+
+// timeoutContext returns a context that expires after timeout, or one that
+// is already done if timeout <= 0.
+func timeoutContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx, cancel
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// Drain removes and returns every item currently queued, front first,
+// without waiting.
+func (q *BlockingQueue) Drain() []int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	values := q.items.Values()
+	if len(values) > 0 {
+		q.items.Clear()
+		q.signal()
+	}
+	return values
+}
+
+// Close stops the queue from accepting items and wakes every waiter.
+// Closing a closed queue has no effect.
+func (q *BlockingQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		q.signal()
+	}
+}
+
+func (q *BlockingQueue) IsClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+func (q *BlockingQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+func (q *BlockingQueue) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// Cap returns the capacity, or math.MaxInt for an unbounded queue.
+func (q *BlockingQueue) Cap() int {
+	return q.capacity
+}
@@ -0,0 +1,77 @@
+package datastructures
+
+import "io"
+
+// Container is the behaviour every structure in the package shares.
+type Container[T any] interface {
+	Len() int
+	IsEmpty() bool
+	Clear()
+	// Values returns a copy of the elements in the container's natural
+	// iteration order.
+	Values() []T
+	// SerializeTo and DeserializeFrom use the same binary layout as the
+	// file-based Serialize and Deserialize.
+	SerializeTo(w io.Writer) error
+	DeserializeFrom(r io.Reader) error
+}
+
+// Sequence is a Container whose elements have positions.
+type Sequence[T any] interface {
+	Container[T]
+	At(index int) (T, error)
+}
+
+// Map is a Container of values addressed by key.
+type Map[K, V any] interface {
+	Container[V]
+	Insert(key K, value V)
+	Get(key K) (V, bool)
+	Remove(key K)
+	Keys() []K
+}
+
+// OrderedSet is a Container of unique keys kept in ascending order.
+type OrderedSet[K any] interface {
+	Container[K]
+	Insert(key K)
+	Remove(key K)
+	Find(key K) bool
+}
+
+// Cache is a bounded key-value store that evicts entries to make room.
+// Get counts a hit or a miss in Stats and may change which entry is
+// evicted next; Peek does neither.
+type Cache[K, V any] interface {
+	Get(key K) (V, bool)
+	Peek(key K) (V, bool)
+	Put(key K, value V)
+	Remove(key K) bool
+	// SetOnEvict registers a callback for entries evicted to make room.
+	SetOnEvict(fn func(key K, value V))
+	Len() int
+	Cap() int
+	Clear()
+	Stats() CacheStats
+	ResetStats()
+}
+
+var (
+	_ Sequence[int]   = (*MyArray[int])(nil)
+	_ Sequence[int]   = (*SinglyLinkedList)(nil)
+	_ Sequence[int]   = (*DoublyLinkedList)(nil)
+	_ Sequence[int]   = (*MyStack)(nil)
+	_ Sequence[int]   = (*MyQueue)(nil)
+	_ Sequence[int]   = (*Deque[int])(nil)
+	_ Map[int, int]   = (*HashTableChain[int, int])(nil)
+	_ Map[int, int]   = (*HashTableOpen[int, int])(nil)
+	_ Map[int, int]   = (*HashTableCuckoo[int, int])(nil)
+	_ OrderedSet[int] = (*AVLTree[int, struct{}])(nil)
+	_ OrderedSet[int] = (*SkipList[int, struct{}])(nil)
+	_ OrderedSet[int] = (*RedBlackTree[int, struct{}])(nil)
+	_ Container[int]  = (*PriorityQueue[int])(nil)
+	_ Container[int]  = (*IndexedPriorityQueue[int, int])(nil)
+	_ Cache[int, int] = (*LRUCache[int, int])(nil)
+	_ Cache[int, int] = (*LFUCache[int, int])(nil)
+	_ Cache[int, int] = (*ARCCache[int, int])(nil)
+)
@@ -5,19 +5,39 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 )
 
+// DNode is a list node. The nodes returned by PushFront and PushBack are
+// handles that MoveToFront, MoveToBack and RemoveNode accept in O(1).
 type DNode struct {
 	data int
 	next *DNode
 	prev *DNode
+	list *DoublyLinkedList // nil once the node is removed
+}
+
+func (n *DNode) Value() int {
+	return n.data
+}
+
+// Next returns the following node, or nil at the tail.
+func (n *DNode) Next() *DNode {
+	return n.next
+}
+
+// Prev returns the preceding node, or nil at the head.
+func (n *DNode) Prev() *DNode {
+	return n.prev
 }
 
 type DoublyLinkedList struct {
-	head *DNode
-	tail *DNode
-	size int
+	head    *DNode
+	tail    *DNode
+	size    int
+	version int
 }
 
 func NewDoublyLinkedList() *DoublyLinkedList {
@@ -32,30 +52,124 @@ func (d *DoublyLinkedList) GetSize() int {
 	return d.size
 }
 
-func (d *DoublyLinkedList) PushFront(value int) {
-	newNode := &DNode{data: value, next: nil, prev: nil}
+// Front returns the head node, or nil if the list is empty.
+func (d *DoublyLinkedList) Front() *DNode {
+	return d.head
+}
+
+// Back returns the tail node, or nil if the list is empty.
+func (d *DoublyLinkedList) Back() *DNode {
+	return d.tail
+}
+
+func (d *DoublyLinkedList) linkFront(n *DNode) {
+	n.list = d
+	n.prev = nil
+	n.next = d.head
 	if d.head == nil {
-		d.head = newNode
-		d.tail = newNode
+		d.tail = n
 	} else {
-		newNode.next = d.head
-		d.head.prev = newNode
-		d.head = newNode
+		d.head.prev = n
 	}
+	d.head = n
 	d.size++
+	d.version++
 }
 
-func (d *DoublyLinkedList) PushBack(value int) {
-	newNode := &DNode{data: value, next: nil, prev: nil}
+func (d *DoublyLinkedList) linkBack(n *DNode) {
+	n.list = d
+	n.next = nil
+	n.prev = d.tail
 	if d.tail == nil {
-		d.head = newNode
-		d.tail = newNode
+		d.head = n
 	} else {
-		d.tail.next = newNode
-		newNode.prev = d.tail
-		d.tail = newNode
+		d.tail.next = n
 	}
+	d.tail = n
 	d.size++
+	d.version++
+}
+
+// unlink detaches n, which must belong to d.
+func (d *DoublyLinkedList) unlink(n *DNode) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		d.head = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		d.tail = n.prev
+	}
+	n.next = nil
+	n.prev = nil
+	n.list = nil
+	d.size--
+	d.version++
+}
+
+// PushFront adds value at the head and returns its node.
+func (d *DoublyLinkedList) PushFront(value int) *DNode {
+	newNode := &DNode{data: value}
+	d.linkFront(newNode)
+	return newNode
+}
+
+// PushBack adds value at the tail and returns its node.
+func (d *DoublyLinkedList) PushBack(value int) *DNode {
+	newNode := &DNode{data: value}
+	d.linkBack(newNode)
+	return newNode
+}
+
+// MoveToFront makes n the head in O(1).
+func (d *DoublyLinkedList) MoveToFront(n *DNode) error {
+	if n == nil || n.list != d {
+		return errors.New("node not in list")
+	}
+	if n != d.head {
+		d.unlink(n)
+		d.linkFront(n)
+	}
+	return nil
+}
+
+// MoveToBack makes n the tail in O(1).
+func (d *DoublyLinkedList) MoveToBack(n *DNode) error {
+	if n == nil || n.list != d {
+		return errors.New("node not in list")
+	}
+	if n != d.tail {
+		d.unlink(n)
+		d.linkBack(n)
+	}
+	return nil
+}
+
+// RemoveNode removes n in O(1). Removing a node twice is an error.
+func (d *DoublyLinkedList) RemoveNode(n *DNode) error {
+	if n == nil || n.list != d {
+		return errors.New("node not in list")
+	}
+	d.unlink(n)
+	return nil
+}
+
+// InsertAfterNode adds value right after mark in O(1) and returns its node.
+func (d *DoublyLinkedList) InsertAfterNode(mark *DNode, value int) (*DNode, error) {
+	if mark == nil || mark.list != d {
+		return nil, errors.New("node not in list")
+	}
+	if mark == d.tail {
+		return d.PushBack(value), nil
+	}
+	newNode := &DNode{data: value, next: mark.next, prev: mark, list: d}
+	mark.next.prev = newNode
+	mark.next = newNode
+	d.size++
+	d.version++
+	return newNode, nil
 }
 
 func (d *DoublyLinkedList) InsertAfter(index int, value int) error {
@@ -70,10 +184,11 @@ func (d *DoublyLinkedList) InsertAfter(index int, value int) error {
 	for i := 0; i < index; i++ {
 		curr = curr.next
 	}
-	newNode := &DNode{data: value, next: curr.next, prev: curr}
+	newNode := &DNode{data: value, next: curr.next, prev: curr, list: d}
 	curr.next.prev = newNode
 	curr.next = newNode
 	d.size++
+	d.version++
 	return nil
 }
 
@@ -92,26 +207,14 @@ func (d *DoublyLinkedList) PopFront() {
 	if d.head == nil {
 		return
 	}
-	d.head = d.head.next
-	if d.head != nil {
-		d.head.prev = nil
-	} else {
-		d.tail = nil
-	}
-	d.size--
+	d.unlink(d.head)
 }
 
 func (d *DoublyLinkedList) PopBack() {
 	if d.tail == nil {
 		return
 	}
-	d.tail = d.tail.prev
-	if d.tail != nil {
-		d.tail.next = nil
-	} else {
-		d.head = nil
-	}
-	d.size--
+	d.unlink(d.tail)
 }
 
 func (d *DoublyLinkedList) RemoveAt(index int) error {
@@ -130,10 +233,7 @@ func (d *DoublyLinkedList) RemoveAt(index int) error {
 	for i := 0; i < index; i++ {
 		curr = curr.next
 	}
-	curr.prev.next = curr.next
-	curr.next.prev = curr.prev
-	curr = nil
-	d.size--
+	d.unlink(curr)
 	return nil
 }
 
@@ -141,16 +241,7 @@ func (d *DoublyLinkedList) RemoveByValue(value int) {
 	curr := d.head
 	for curr != nil {
 		if curr.data == value {
-			if curr == d.head {
-				d.PopFront()
-			} else if curr == d.tail {
-				d.PopBack()
-			} else {
-				curr.prev.next = curr.next
-				curr.next.prev = curr.prev
-				curr = nil
-				d.size--
-			}
+			d.unlink(curr)
 			return
 		}
 		curr = curr.next
@@ -168,6 +259,78 @@ func (d *DoublyLinkedList) Find(value int) bool {
 	return false
 }
 
+func (d *DoublyLinkedList) Len() int {
+	return d.size
+}
+
+func (d *DoublyLinkedList) IsEmpty() bool {
+	return d.size == 0
+}
+
+// Clear empties the list. It walks the nodes to detach them, so handles
+// obtained before are rejected by the node methods afterwards.
+func (d *DoublyLinkedList) Clear() {
+	for curr := d.head; curr != nil; {
+		next := curr.next
+		curr.next, curr.prev, curr.list = nil, nil, nil
+		curr = next
+	}
+	d.head = nil
+	d.tail = nil
+	d.size = 0
+	d.version++
+}
+
+func (d *DoublyLinkedList) Values() []int {
+	values := make([]int, 0, d.size)
+	for curr := d.head; curr != nil; curr = curr.next {
+		values = append(values, curr.data)
+	}
+	return values
+}
+
+func (d *DoublyLinkedList) At(index int) (int, error) {
+	if index < 0 || index >= d.size {
+		return 0, errors.New("index out of bounds")
+	}
+	curr := d.head
+	for i := 0; i < index; i++ {
+		curr = curr.next
+	}
+	return curr.data, nil
+}
+
+// All yields index/value pairs from head to tail.
+func (d *DoublyLinkedList) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		version := d.version
+		i := 0
+		for curr := d.head; curr != nil; curr = curr.next {
+			if !yield(i, curr.data) {
+				return
+			}
+			checkVersion(version, d.version)
+			i++
+		}
+	}
+}
+
+// Backward yields index/value pairs from tail to head, with indices
+// counted from the head as in All.
+func (d *DoublyLinkedList) Backward() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		version := d.version
+		i := d.size - 1
+		for curr := d.tail; curr != nil; curr = curr.prev {
+			if !yield(i, curr.data) {
+				return
+			}
+			checkVersion(version, d.version)
+			i--
+		}
+	}
+}
+
 func (d *DoublyLinkedList) Print() {
 	fmt.Print("DoublyLinkedList [")
 	curr := d.head
@@ -189,12 +352,16 @@ func (d *DoublyLinkedList) Serialize(filename string) error {
 	}
 	defer file.Close()
 
-	if err := binary.Write(file, binary.LittleEndian, uint64(d.size)); err != nil {
+	return d.SerializeTo(file)
+}
+
+func (d *DoublyLinkedList) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(d.size)); err != nil {
 		return err
 	}
 	curr := d.head
 	for curr != nil {
-		if err := binary.Write(file, binary.LittleEndian, int32(curr.data)); err != nil {
+		if err := binary.Write(w, binary.LittleEndian, int32(curr.data)); err != nil {
 			return err
 		}
 		curr = curr.next
@@ -209,13 +376,15 @@ func (d *DoublyLinkedList) Deserialize(filename string) error {
 	}
 	defer file.Close()
 
+	return d.DeserializeFrom(file)
+}
+
+func (d *DoublyLinkedList) DeserializeFrom(r io.Reader) error {
 	// Clear existing list
-	d.head = nil
-	d.tail = nil
-	d.size = 0
+	d.Clear()
 
 	var fileSize uint64
-	if err := binary.Read(file, binary.LittleEndian, &fileSize); err != nil {
+	if err := binary.Read(r, binary.LittleEndian, &fileSize); err != nil {
 		return err
 	}
 
@@ -225,7 +394,7 @@ func (d *DoublyLinkedList) Deserialize(filename string) error {
 
 	for i := uint64(0); i < fileSize; i++ {
 		var value int32
-		if err := binary.Read(file, binary.LittleEndian, &value); err != nil {
+		if err := binary.Read(r, binary.LittleEndian, &value); err != nil {
 			return err
 		}
 		d.PushBack(int(value))
@@ -266,9 +435,7 @@ func (d *DoublyLinkedList) DeserializeJSON(filename string) error {
 	defer file.Close()
 
 	// Clear existing list
-	d.head = nil
-	d.tail = nil
-	d.size = 0
+	d.Clear()
 
 	var listData doublyListJSON
 	decoder := json.NewDecoder(file)
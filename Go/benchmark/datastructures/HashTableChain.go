@@ -3,86 +3,359 @@ package datastructures
 import (
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
+	"io"
+	"iter"
 	"os"
 )
 
-type ChainNode struct {
-	key   int
-	value int
-	next  *ChainNode
+type ChainNode[K, V any] struct {
+	key   K
+	value V
+	next  *ChainNode[K, V]
 }
 
-type HashTableChain struct {
-	table    []*ChainNode
-	size     int
-	capacity int
+// HashTableChain is a separate-chaining hash table. With incremental
+// rehashing, a resize only allocates the new table; each Put of a new key
+// and each Remove that deletes one then moves rehashBuckets old buckets
+// over. Only those writes migrate: Get leaves both tables alone, so it is
+// safe for concurrent readers, and until enough writes have happened a
+// lookup may probe both tables.
+type HashTableChain[K, V any] struct {
+	table       []*ChainNode[K, V]
+	size        int
+	capacity    int
+	minCapacity int // the table never shrinks below this
+	maxLoad     float64
+	minLoad     float64
+	hasher      Hasher[K]
+	equal       func(a, b K) bool
+	version     int
+
+	// Incremental rehashing: while oldTable is set, its buckets from
+	// migrateIdx on have not been moved into table yet. size counts the
+	// entries of both.
+	incremental bool
+	oldTable    []*ChainNode[K, V]
+	migrateIdx  int
 }
 
-func NewHashTableChain(initCap int) *HashTableChain {
+// Default load factors for HashTableChain: the table doubles once it holds
+// more entries than buckets and halves when under a quarter full.
+const (
+	DefaultChainMaxLoad = 1.0
+	DefaultChainMinLoad = 0.25
+)
+
+// rehashBuckets is how many old buckets a Put of a new key or a successful
+// Remove migrates during an incremental rehash. Four is enough to drain
+// the old table before a shrink to half the capacity can be followed by
+// the next resize.
+const rehashBuckets = 4
+
+func NewHashTableChain(initCap int) *HashTableChain[int, int] {
+	return NewHashTableChainOf[int, int](initCap, IntHasher, nil)
+}
+
+// NewHashTableChainOf builds a table for any key type. A nil hasher or
+// equal falls back to the defaults for int, string and []byte keys; other
+// key types must supply a hasher.
+func NewHashTableChainOf[K, V any](initCap int, hasher Hasher[K], equal func(a, b K) bool) *HashTableChain[K, V] {
 	if initCap <= 0 {
 		initCap = 8
 	}
-	return &HashTableChain{
-		table:    make([]*ChainNode, initCap),
-		size:     0,
-		capacity: initCap,
+	hasher, equal = resolveHashing(hasher, equal)
+	return &HashTableChain[K, V]{
+		table:       make([]*ChainNode[K, V], initCap),
+		size:        0,
+		capacity:    initCap,
+		minCapacity: initCap,
+		maxLoad:     DefaultChainMaxLoad,
+		minLoad:     DefaultChainMinLoad,
+		hasher:      hasher,
+		equal:       equal,
+	}
+}
+
+// SetLoadFactors changes the resize policy. The table grows when
+// size/capacity exceeds maxLoad and shrinks when it falls below minLoad;
+// minLoad 0 disables shrinking. minLoad must stay below maxLoad/2 so a
+// resize cannot immediately trigger the opposite one.
+func (h *HashTableChain[K, V]) SetLoadFactors(maxLoad, minLoad float64) error {
+	if maxLoad <= 0 || minLoad < 0 || minLoad >= maxLoad/2 {
+		return fmt.Errorf("invalid load factors: max %v, min %v", maxLoad, minLoad)
+	}
+	h.maxLoad = maxLoad
+	h.minLoad = minLoad
+	h.maybeGrow()
+	return nil
+}
+
+// Reserve makes room for n entries without further rehashing and keeps the
+// table from shrinking below that capacity.
+func (h *HashTableChain[K, V]) Reserve(n int) {
+	needed := h.capacity
+	for float64(n) > float64(needed)*h.maxLoad {
+		needed *= 2
+	}
+	if needed > h.minCapacity {
+		h.minCapacity = needed
+	}
+	if needed > h.capacity {
+		h.rehash(needed)
+	}
+}
+
+// SetIncrementalRehash switches between rehashing the whole table inside
+// the Put or Remove that crosses a load factor, and keeping the old table
+// next to the new one and migrating a few buckets on every Put and Remove.
+// Lookups then check both tables. Disabling it completes a pending rehash.
+func (h *HashTableChain[K, V]) SetIncrementalRehash(enabled bool) {
+	h.incremental = enabled
+	if !enabled {
+		h.finishRehash()
+	}
+}
+
+// Rehashing reports whether an incremental rehash is in progress.
+func (h *HashTableChain[K, V]) Rehashing() bool {
+	return h.oldTable != nil
+}
+
+func (h *HashTableChain[K, V]) Capacity() int {
+	return h.capacity
+}
+
+func (h *HashTableChain[K, V]) LoadFactor() float64 {
+	return float64(h.size) / float64(h.capacity)
+}
+
+// rehash moves every node into a table of newCap buckets, reusing the
+// nodes themselves. In incremental mode the current table only becomes
+// oldTable and rehashStep moves it over later.
+func (h *HashTableChain[K, V]) rehash(newCap int) {
+	h.finishRehash()
+	h.oldTable = h.table
+	h.migrateIdx = 0
+	h.capacity = newCap
+	h.table = make([]*ChainNode[K, V], newCap)
+	h.version++
+	if !h.incremental {
+		h.finishRehash()
+	}
+}
+
+// moveBucket relinks the nodes of old bucket i into the current table.
+func (h *HashTableChain[K, V]) moveBucket(i int) {
+	node := h.oldTable[i]
+	for node != nil {
+		next := node.next
+		idx := h.hash(node.key)
+		node.next = h.table[idx]
+		h.table[idx] = node
+		node = next
+	}
+	h.oldTable[i] = nil
+}
+
+// rehashStep migrates the next rehashBuckets buckets of the old table.
+func (h *HashTableChain[K, V]) rehashStep() {
+	if h.oldTable == nil {
+		return
+	}
+	for n := 0; n < rehashBuckets && h.migrateIdx < len(h.oldTable); n++ {
+		h.moveBucket(h.migrateIdx)
+		h.migrateIdx++
+	}
+	if h.migrateIdx == len(h.oldTable) {
+		h.oldTable = nil
 	}
+	h.version++
 }
 
-func (h *HashTableChain) hash(key int) int {
-	return int(math.Abs(float64(key))) % h.capacity
+func (h *HashTableChain[K, V]) finishRehash() {
+	if h.oldTable == nil {
+		return
+	}
+	for ; h.migrateIdx < len(h.oldTable); h.migrateIdx++ {
+		h.moveBucket(h.migrateIdx)
+	}
+	h.oldTable = nil
+}
+
+func (h *HashTableChain[K, V]) maybeGrow() {
+	newCap := h.capacity
+	for float64(h.size) > float64(newCap)*h.maxLoad {
+		newCap *= 2
+	}
+	if newCap != h.capacity {
+		h.rehash(newCap)
+	}
+}
+
+func (h *HashTableChain[K, V]) maybeShrink() {
+	newCap := h.capacity
+	for newCap/2 >= h.minCapacity && float64(h.size) < float64(newCap)*h.minLoad {
+		newCap /= 2
+	}
+	if newCap != h.capacity {
+		h.rehash(newCap)
+	}
+}
+
+func (h *HashTableChain[K, V]) hash(key K) int {
+	return int(h.hasher(key) % uint64(h.capacity))
+}
+
+// findNode returns the node holding key in the current table or, during
+// an incremental rehash, in the old one.
+func (h *HashTableChain[K, V]) findNode(key K) *ChainNode[K, V] {
+	for curr := h.table[h.hash(key)]; curr != nil; curr = curr.next {
+		if h.equal(curr.key, key) {
+			return curr
+		}
+	}
+	if h.oldTable != nil {
+		idx := int(h.hasher(key) % uint64(len(h.oldTable)))
+		for curr := h.oldTable[idx]; curr != nil; curr = curr.next {
+			if h.equal(curr.key, key) {
+				return curr
+			}
+		}
+	}
+	return nil
 }
 
-func (h *HashTableChain) Insert(key, value int) {
+// Put stores value under key, replacing the value if key is present. An
+// update is done in place and migrates nothing, so it does not disturb a
+// running All loop.
+func (h *HashTableChain[K, V]) Put(key K, value V) {
+	if node := h.findNode(key); node != nil {
+		node.value = value
+		return
+	}
+
+	h.rehashStep()
 	idx := h.hash(key)
-	newNode := &ChainNode{key: key, value: value, next: h.table[idx]}
+	newNode := &ChainNode[K, V]{key: key, value: value, next: h.table[idx]}
 	h.table[idx] = newNode
 	h.size++
+	h.version++
+	h.maybeGrow()
 }
 
-func (h *HashTableChain) Get(key int) (int, bool) {
-	idx := h.hash(key)
-	curr := h.table[idx]
-	for curr != nil {
-		if curr.key == key {
-			return curr.value, true
-		}
-		curr = curr.next
+// Insert is Put under the name the Map interface uses.
+func (h *HashTableChain[K, V]) Insert(key K, value V) {
+	h.Put(key, value)
+}
+
+func (h *HashTableChain[K, V]) Get(key K) (V, bool) {
+	if node := h.findNode(key); node != nil {
+		return node.value, true
 	}
-	return 0, false
+	var zero V
+	return zero, false
 }
 
-func (h *HashTableChain) Remove(key int) {
-	idx := h.hash(key)
-	curr := h.table[idx]
-	var prev *ChainNode
+func (h *HashTableChain[K, V]) Remove(key K) {
+	removed := h.unlink(h.table, h.hash(key), key)
+	if !removed && h.oldTable != nil {
+		removed = h.unlink(h.oldTable, int(h.hasher(key)%uint64(len(h.oldTable))), key)
+	}
+	if removed {
+		h.size--
+		h.version++
+		h.rehashStep()
+		h.maybeShrink()
+	}
+}
+
+// unlink removes key from bucket idx of table and reports whether it was
+// there.
+func (h *HashTableChain[K, V]) unlink(table []*ChainNode[K, V], idx int, key K) bool {
+	curr := table[idx]
+	var prev *ChainNode[K, V]
 
 	for curr != nil {
-		if curr.key == key {
+		if h.equal(curr.key, key) {
 			if prev != nil {
 				prev.next = curr.next
 			} else {
-				h.table[idx] = curr.next
+				table[idx] = curr.next
 			}
-			curr = nil
-			h.size--
-			return
+			return true
 		}
 		prev = curr
 		curr = curr.next
 	}
+	return false
+}
+
+func (h *HashTableChain[K, V]) Len() int {
+	return h.size
+}
+
+func (h *HashTableChain[K, V]) IsEmpty() bool {
+	return h.size == 0
+}
+
+func (h *HashTableChain[K, V]) Keys() []K {
+	keys := make([]K, 0, h.size)
+	for k := range h.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (h *HashTableChain[K, V]) Values() []V {
+	values := make([]V, 0, h.size)
+	for _, v := range h.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+func (h *HashTableChain[K, V]) Clear() {
+	h.table = make([]*ChainNode[K, V], h.capacity)
+	h.oldTable = nil
+	h.migrateIdx = 0
+	h.size = 0
+	h.version++
+}
+
+// All yields key/value pairs in bucket order, followed by the buckets an
+// incremental rehash has not moved yet. It does not migrate anything, so
+// it is safe to run alongside other readers.
+func (h *HashTableChain[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		version := h.version
+		tables := [][]*ChainNode[K, V]{h.table}
+		if h.oldTable != nil {
+			tables = append(tables, h.oldTable)
+		}
+		for _, table := range tables {
+			for i := range table {
+				for curr := table[i]; curr != nil; curr = curr.next {
+					if !yield(curr.key, curr.value) {
+						return
+					}
+					checkVersion(version, h.version)
+				}
+			}
+		}
+	}
 }
 
-func (h *HashTableChain) Print() {
+func (h *HashTableChain[K, V]) Print() {
+	h.finishRehash()
 	fmt.Println("HashTableChain:")
 	for i := 0; i < h.capacity; i++ {
 		if h.table[i] != nil {
 			fmt.Printf("[%d]: ", i)
 			curr := h.table[i]
 			for curr != nil {
-				fmt.Printf("(%d->%d)", curr.key, curr.value)
+				fmt.Printf("(%v->%v)", curr.key, curr.value)
 				if curr.next != nil {
 					fmt.Print(" -> ")
 				}
@@ -94,17 +367,24 @@ func (h *HashTableChain) Print() {
 }
 
 // Binary Serialization
-func (h *HashTableChain) Serialize(filename string) error {
+func (h *HashTableChain[K, V]) Serialize(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for writing: %w", err)
 	}
 	defer file.Close()
 
-	if err := binary.Write(file, binary.LittleEndian, uint64(h.size)); err != nil {
+	return h.SerializeTo(file)
+}
+
+// SerializeTo writes the bucket layout, so it completes a pending
+// incremental rehash first.
+func (h *HashTableChain[K, V]) SerializeTo(w io.Writer) error {
+	h.finishRehash()
+	if err := binary.Write(w, binary.LittleEndian, uint64(h.size)); err != nil {
 		return err
 	}
-	if err := binary.Write(file, binary.LittleEndian, uint64(h.capacity)); err != nil {
+	if err := binary.Write(w, binary.LittleEndian, uint64(h.capacity)); err != nil {
 		return err
 	}
 
@@ -116,16 +396,16 @@ func (h *HashTableChain) Serialize(filename string) error {
 			temp = temp.next
 		}
 
-		if err := binary.Write(file, binary.LittleEndian, chainSize); err != nil {
+		if err := binary.Write(w, binary.LittleEndian, chainSize); err != nil {
 			return err
 		}
 
 		curr := h.table[i]
 		for curr != nil {
-			if err := binary.Write(file, binary.LittleEndian, int32(curr.key)); err != nil {
+			if err := writeValue(w, curr.key); err != nil {
 				return err
 			}
-			if err := binary.Write(file, binary.LittleEndian, int32(curr.value)); err != nil {
+			if err := writeValue(w, curr.value); err != nil {
 				return err
 			}
 			curr = curr.next
@@ -134,90 +414,87 @@ func (h *HashTableChain) Serialize(filename string) error {
 	return nil
 }
 
-func (h *HashTableChain) Deserialize(filename string) error {
+func (h *HashTableChain[K, V]) Deserialize(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for reading: %w", err)
 	}
 	defer file.Close()
 
-	// Clear existing data
-	for i := 0; i < h.capacity; i++ {
-		node := h.table[i]
-		for node != nil {
-			node = node.next
-		}
-	}
+	return h.DeserializeFrom(file)
+}
 
+func (h *HashTableChain[K, V]) DeserializeFrom(r io.Reader) error {
 	var size, capacity uint64
-	if err := binary.Read(file, binary.LittleEndian, &size); err != nil {
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
 		return err
 	}
-	if err := binary.Read(file, binary.LittleEndian, &capacity); err != nil {
+	if err := binary.Read(r, binary.LittleEndian, &capacity); err != nil {
 		return err
 	}
 
-	h.size = int(size)
-	h.capacity = int(capacity)
-	h.table = make([]*ChainNode, h.capacity)
-
-	for i := 0; i < h.capacity; i++ {
-		h.table[i] = nil
+	if capacity == 0 {
+		return errors.New("invalid capacity in file")
 	}
 
+	// Restore the saved capacity so a table that grew keeps its size
+	h.size = 0
+	h.version++
+	h.capacity = int(capacity)
+	h.table = make([]*ChainNode[K, V], h.capacity)
+	h.oldTable = nil
+	h.migrateIdx = 0
+
 	for i := 0; i < h.capacity; i++ {
 		var chainSize uint64
-		if err := binary.Read(file, binary.LittleEndian, &chainSize); err != nil {
+		if err := binary.Read(r, binary.LittleEndian, &chainSize); err != nil {
 			return err
 		}
 
 		for j := uint64(0); j < chainSize; j++ {
-			var key, value int32
-			if err := binary.Read(file, binary.LittleEndian, &key); err != nil {
+			key, err := readValue[K](r)
+			if err != nil {
 				return err
 			}
-			if err := binary.Read(file, binary.LittleEndian, &value); err != nil {
+			value, err := readValue[V](r)
+			if err != nil {
 				return err
 			}
-			h.Insert(int(key), int(value))
+			h.Insert(key, value)
 		}
 	}
 	return nil
 }
 
 // JSON Serialization
-type chainEntry struct {
-	Key   int `json:"key"`
-	Value int `json:"value"`
+type chainEntry[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
 }
 
-type hashTableChainJSON struct {
-	Entries []chainEntry `json:"entries"`
+type hashTableChainJSON[K, V any] struct {
+	Entries []chainEntry[K, V] `json:"entries"`
 }
 
-func (h *HashTableChain) SerializeJSON(filename string) error {
+func (h *HashTableChain[K, V]) SerializeJSON(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for writing: %w", err)
 	}
 	defer file.Close()
 
-	entries := make([]chainEntry, 0, h.size)
-	for i := 0; i < h.capacity; i++ {
-		curr := h.table[i]
-		for curr != nil {
-			entries = append(entries, chainEntry{Key: curr.key, Value: curr.value})
-			curr = curr.next
-		}
+	entries := make([]chainEntry[K, V], 0, h.size)
+	for k, v := range h.All() {
+		entries = append(entries, chainEntry[K, V]{Key: k, Value: v})
 	}
 
-	data := hashTableChainJSON{Entries: entries}
+	data := hashTableChainJSON[K, V]{Entries: entries}
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
 
-func (h *HashTableChain) DeserializeJSON(filename string) error {
+func (h *HashTableChain[K, V]) DeserializeJSON(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for reading: %w", err)
@@ -232,10 +509,13 @@ func (h *HashTableChain) DeserializeJSON(filename string) error {
 		}
 	}
 
-	h.table = make([]*ChainNode, h.capacity)
+	h.table = make([]*ChainNode[K, V], h.capacity)
+	h.oldTable = nil
+	h.migrateIdx = 0
 	h.size = 0
+	h.version++
 
-	var data hashTableChainJSON
+	var data hashTableChainJSON[K, V]
 	decoder := json.NewDecoder(file)
 	if err := decoder.Decode(&data); err != nil {
 		return err
@@ -3,227 +3,679 @@ package datastructures
 import (
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
+	"io"
+	"iter"
 	"os"
 )
 
-type HashEntry struct {
-	key        int
-	value      int
+type HashEntry[K, V any] struct {
+	key        K
+	value      V
 	isOccupied bool
 	isDeleted  bool
+	dist       int // probe distance from the home slot
 }
 
-type HashTableOpen struct {
-	table    []HashEntry
+// ProbeStats describes how far entries sit from their home slot.
+type ProbeStats struct {
+	AverageDistance float64
+	MaxDistance     int
+}
+
+type HashTableOpen[K, V any] struct {
+	table    []HashEntry[K, V]
+	ctrl     []byte // control bytes, only used by GroupProbing
 	size     int
+	deleted  int // tombstones, not used by linear probing
 	capacity int
+	probing  ProbeStrategy
+	hasher   Hasher[K]
+	equal    func(a, b K) bool
+	version  int
+
+	// Incremental rehashing: while old is set, its live entries from slot
+	// migrateIdx on have not been moved into table yet.
+	incremental bool
+	old         *HashTableOpen[K, V]
+	migrateIdx  int
+}
+
+// ProbeStrategy selects how HashTableOpen looks for the next slot after a
+// collision.
+type ProbeStrategy int
+
+const (
+	// LinearProbing scans consecutive slots using Robin Hood displacement
+	// and backward-shift deletion.
+	LinearProbing ProbeStrategy = iota
+	// QuadraticProbing visits home + i(i+1)/2 on power-of-two capacities,
+	// which reaches every slot, and home + i*i otherwise. Non-power-of-two
+	// tables grow to prime capacities, where i*i reaches half the slots.
+	QuadraticProbing
+	// DoubleHashing steps by a second hash of the key, chosen coprime with
+	// the capacity so every slot is reachable.
+	DoubleHashing
+	// GroupProbing is a Swiss table layout: slots come in groups of 8 with a
+	// separate array of control bytes holding 7-bit hash fingerprints, so a
+	// lookup compares a whole group with a few word operations and only
+	// touches entries whose fingerprint matches.
+	GroupProbing
+)
+
+func (p ProbeStrategy) String() string {
+	switch p {
+	case LinearProbing:
+		return "Linear"
+	case QuadraticProbing:
+		return "Quadratic"
+	case DoubleHashing:
+		return "DoubleHashing"
+	case GroupProbing:
+		return "Group"
+	}
+	return fmt.Sprintf("ProbeStrategy(%d)", int(p))
+}
+
+// HashTableOpenOptions tunes a HashTableOpen. The zero value gives the
+// default Robin Hood linear probing.
+type HashTableOpenOptions struct {
+	Probing ProbeStrategy
+	// IncrementalRehash spreads a resize over the following operations:
+	// the old table is kept and every Insert or Remove moves a few of its
+	// slots, instead of one Insert rehashing every entry.
+	IncrementalRehash bool
+}
+
+// rehashSlots is how many old slots an Insert of a new key or a successful
+// Remove migrates while an incremental rehash is in progress; updates and
+// Get migrate nothing. The old table is drained long before the new one
+// can fill up.
+const rehashSlots = 16
+
+func NewHashTableOpen(initCap int, opts ...HashTableOpenOptions) *HashTableOpen[int, int] {
+	return NewHashTableOpenOf[int, int](initCap, IntHasher, nil, opts...)
 }
 
-func NewHashTableOpen(initCap int) *HashTableOpen {
+// NewHashTableOpenOf builds a table for any key type. A nil hasher or
+// equal falls back to the defaults for int, string and []byte keys; other
+// key types must supply a hasher.
+func NewHashTableOpenOf[K, V any](initCap int, hasher Hasher[K], equal func(a, b K) bool, opts ...HashTableOpenOptions) *HashTableOpen[K, V] {
 	if initCap <= 0 {
 		initCap = 8
 	}
-	table := make([]HashEntry, initCap)
-	for i := 0; i < initCap; i++ {
-		table[i] = HashEntry{isOccupied: false, isDeleted: false}
+	hasher, equal = resolveHashing(hasher, equal)
+	var opt HashTableOpenOptions
+	if len(opts) > 0 {
+		opt = opts[len(opts)-1]
+	}
+	if opt.Probing < LinearProbing || opt.Probing > GroupProbing {
+		panic("datastructures: unknown probe strategy")
 	}
-	return &HashTableOpen{
-		table:    table,
-		size:     0,
-		capacity: initCap,
+	h := &HashTableOpen[K, V]{
+		probing:     opt.Probing,
+		hasher:      hasher,
+		equal:       equal,
+		incremental: opt.IncrementalRehash,
 	}
+	h.resetTable(initCap)
+	return h
 }
 
-func (h *HashTableOpen) hash(key int) int {
-	return int(math.Abs(float64(key))) % h.capacity
+// resetTable replaces the slots with an empty table of the given capacity,
+// rounded up to whole groups for GroupProbing.
+func (h *HashTableOpen[K, V]) resetTable(capacity int) {
+	if h.probing == GroupProbing {
+		capacity = groupCapacity(capacity)
+		h.ctrl = make([]byte, capacity)
+		for i := range h.ctrl {
+			h.ctrl[i] = ctrlEmpty
+		}
+	}
+	h.capacity = capacity
+	h.table = make([]HashEntry[K, V], capacity)
+	h.size = 0
+	h.deleted = 0
+	h.old = nil
+	h.migrateIdx = 0
+	h.version++
 }
 
-func (h *HashTableOpen) resize() {
-	oldCapacity := h.capacity
-	oldTable := h.table
+func (h *HashTableOpen[K, V]) Probing() ProbeStrategy {
+	return h.probing
+}
 
-	h.capacity *= 2
-	h.table = make([]HashEntry, h.capacity)
-	for i := 0; i < h.capacity; i++ {
-		h.table[i] = HashEntry{isOccupied: false, isDeleted: false}
+func (h *HashTableOpen[K, V]) Capacity() int {
+	return h.capacity
+}
+
+func (h *HashTableOpen[K, V]) hash(key K) int {
+	return int(h.hasher(key) % uint64(h.capacity))
+}
+
+// probeStep returns the double hashing step for a key hash: a value in
+// [1, capacity) that shares no factor with the capacity.
+func (h *HashTableOpen[K, V]) probeStep(hv uint64) int {
+	if h.capacity < 2 {
+		return 1
+	}
+	step := int(mix64(hv)%uint64(h.capacity-1)) + 1
+	for gcd(step, h.capacity) != 1 {
+		step++
 	}
+	return step
+}
 
-	h.size = 0
+// probeIndex returns the i-th slot of the probe sequence for the
+// non-linear strategies.
+func (h *HashTableOpen[K, V]) probeIndex(home, step, i int) int {
+	if h.probing == DoubleHashing {
+		return (home + i*step) % h.capacity
+	}
+	if isPowerOfTwo(h.capacity) {
+		return (home + i*(i+1)/2) % h.capacity
+	}
+	return (home + i*i) % h.capacity
+}
+
+// nextCapacity keeps quadratic probing on prime capacities once the table
+// is not a power of two; everything else doubles.
+func (h *HashTableOpen[K, V]) nextCapacity() int {
+	if h.probing == QuadraticProbing && !isPowerOfTwo(h.capacity) {
+		return nextPrime(2 * h.capacity)
+	}
+	return h.capacity * 2
+}
+
+func (h *HashTableOpen[K, V]) resize() {
+	h.rehash(h.nextCapacity())
+}
+
+// rehash moves the live entries into a table of newCap slots, dropping
+// tombstones. With IncrementalRehash the current table is only set aside
+// as h.old and drained by rehashStep.
+func (h *HashTableOpen[K, V]) rehash(newCap int) {
+	if h.incremental {
+		h.finishRehash()
+		old := *h
+		h.resetTable(newCap)
+		h.old = &old
+		return
+	}
+
+	oldCapacity := h.capacity
+	oldTable := h.table
+
+	h.resetTable(newCap)
 	for i := 0; i < oldCapacity; i++ {
 		if oldTable[i].isOccupied && !oldTable[i].isDeleted {
-			h.Insert(oldTable[i].key, oldTable[i].value)
+			h.insertEntry(oldTable[i].key, oldTable[i].value)
+		}
+	}
+}
+
+// rehashStep migrates up to rehashSlots slots of the old table. Entries
+// are removed from the old table through its own Remove, so lookups in it
+// stay correct for whatever has not been moved yet. A linear-probing
+// removal can shift the next entry into the current slot, which is why
+// the slot is only passed once it is free.
+func (h *HashTableOpen[K, V]) rehashStep() {
+	old := h.old
+	if old == nil {
+		return
+	}
+	for n := 0; n < rehashSlots && h.migrateIdx < old.capacity; n++ {
+		e := old.table[h.migrateIdx]
+		if !e.isOccupied || e.isDeleted {
+			h.migrateIdx++
+			continue
+		}
+		old.Remove(e.key)
+		h.insertEntry(e.key, e.value)
+		if h.old != old {
+			return
+		}
+	}
+	if old.size == 0 || h.migrateIdx >= old.capacity {
+		h.old = nil
+		h.migrateIdx = 0
+	}
+}
+
+// finishRehash moves everything left in the old table at once.
+func (h *HashTableOpen[K, V]) finishRehash() {
+	old := h.old
+	if old == nil {
+		return
+	}
+	start := h.migrateIdx
+	h.old = nil
+	h.migrateIdx = 0
+	for i := start; i < old.capacity; i++ {
+		if old.table[i].isOccupied && !old.table[i].isDeleted {
+			h.insertEntry(old.table[i].key, old.table[i].value)
 		}
 	}
 }
 
-func (h *HashTableOpen) Insert(key, value int) {
+// Rehashing reports whether an incremental rehash is in progress.
+func (h *HashTableOpen[K, V]) Rehashing() bool {
+	return h.old != nil
+}
+
+// Insert stores value under key, replacing the value if key is present.
+// Existing keys are updated in place first, so an update never resizes or
+// migrates anything and a running All loop goes on. This also keeps
+// insertEntry from starting a rehash before it looks for the key, which
+// would leave the key behind in the old table.
+func (h *HashTableOpen[K, V]) Insert(key K, value V) {
+	if idx := h.find(key); idx >= 0 {
+		h.table[idx].value = value
+		return
+	}
+	if h.old != nil {
+		if idx := h.old.find(key); idx >= 0 {
+			h.old.table[idx].value = value
+			return
+		}
+	}
+	h.rehashStep()
+	h.insertEntry(key, value)
+}
+
+// insertEntry adds key to the current table. Linear probing uses Robin
+// Hood: an entry that is further from its home slot than the one it meets
+// takes that slot, and the displaced entry continues probing. This keeps
+// probe distances short and uniform, and lets lookups stop as soon as they
+// meet an entry closer to home than the key would be.
+func (h *HashTableOpen[K, V]) insertEntry(key K, value V) {
+	switch h.probing {
+	case QuadraticProbing, DoubleHashing:
+		h.insertProbing(key, value)
+		return
+	case GroupProbing:
+		h.insertGroup(key, value)
+		return
+	}
 	if float64(h.size) >= float64(h.capacity)*0.7 {
 		h.resize()
 	}
 
 	idx := h.hash(key)
-	startIdx := idx
+	dist := 0
+	for h.table[idx].isOccupied && h.table[idx].dist >= dist {
+		if h.equal(h.table[idx].key, key) {
+			h.table[idx].value = value
+			return
+		}
+		idx = (idx + 1) % h.capacity
+		dist++
+	}
 
-	for h.table[idx].isOccupied && !h.table[idx].isDeleted && h.table[idx].key != key {
+	entry := HashEntry[K, V]{key: key, value: value, isOccupied: true, dist: dist}
+	for h.table[idx].isOccupied {
+		if h.table[idx].dist < entry.dist {
+			entry, h.table[idx] = h.table[idx], entry
+		}
 		idx = (idx + 1) % h.capacity
-		if idx == startIdx {
+		entry.dist++
+	}
+	h.table[idx] = entry
+	h.size++
+	h.version++
+}
+
+// insertProbing is Insert for quadratic probing and double hashing. Removed
+// slots stay behind as tombstones and count towards the load factor until
+// the next rehash; a key reuses the first tombstone on its probe sequence.
+func (h *HashTableOpen[K, V]) insertProbing(key K, value V) {
+	if float64(h.size+h.deleted) >= float64(h.capacity)*0.7 {
+		if h.deleted > h.size {
+			h.rehash(h.capacity)
+		} else {
+			h.resize()
+		}
+	}
+
+	hv := h.hasher(key)
+	home := int(hv % uint64(h.capacity))
+	step := 0
+	if h.probing == DoubleHashing {
+		step = h.probeStep(hv)
+	}
+
+	tomb, tombDist := -1, 0
+	for i := 0; i < h.capacity; i++ {
+		idx := h.probeIndex(home, step, i)
+		e := &h.table[idx]
+		if !e.isOccupied {
+			if tomb < 0 {
+				tomb, tombDist = idx, i
+			}
+			break
+		}
+		if e.isDeleted {
+			if tomb < 0 {
+				tomb, tombDist = idx, i
+			}
+			continue
+		}
+		if h.equal(e.key, key) {
+			e.value = value
 			return
 		}
 	}
 
-	h.table[idx].key = key
-	h.table[idx].value = value
-	h.table[idx].isOccupied = true
-	h.table[idx].isDeleted = false
+	if tomb < 0 {
+		// The probe sequence covers only part of the table
+		h.resize()
+		h.insertProbing(key, value)
+		return
+	}
+	if h.table[tomb].isDeleted {
+		h.deleted--
+	}
+	h.table[tomb] = HashEntry[K, V]{key: key, value: value, isOccupied: true, dist: tombDist}
 	h.size++
+	h.version++
 }
 
-func (h *HashTableOpen) Get(key int) (int, bool) {
+// find returns the slot holding key, or -1.
+func (h *HashTableOpen[K, V]) find(key K) int {
+	switch h.probing {
+	case QuadraticProbing, DoubleHashing:
+		return h.findProbing(key)
+	case GroupProbing:
+		return h.findGroup(key)
+	}
 	idx := h.hash(key)
-	startIdx := idx
-
-	for h.table[idx].isOccupied {
-		if !h.table[idx].isDeleted && h.table[idx].key == key {
-			return h.table[idx].value, true
+	for dist := 0; h.table[idx].isOccupied && h.table[idx].dist >= dist; dist++ {
+		if h.equal(h.table[idx].key, key) {
+			return idx
 		}
 		idx = (idx + 1) % h.capacity
-		if idx == startIdx {
-			break
+	}
+	return -1
+}
+
+func (h *HashTableOpen[K, V]) findProbing(key K) int {
+	hv := h.hasher(key)
+	home := int(hv % uint64(h.capacity))
+	step := 0
+	if h.probing == DoubleHashing {
+		step = h.probeStep(hv)
+	}
+	for i := 0; i < h.capacity; i++ {
+		idx := h.probeIndex(home, step, i)
+		e := &h.table[idx]
+		if !e.isOccupied {
+			return -1
+		}
+		if !e.isDeleted && h.equal(e.key, key) {
+			return idx
 		}
 	}
-	return 0, false
+	return -1
 }
 
-func (h *HashTableOpen) Remove(key int) {
-	idx := h.hash(key)
-	startIdx := idx
+func (h *HashTableOpen[K, V]) Get(key K) (V, bool) {
+	if idx := h.find(key); idx >= 0 {
+		return h.table[idx].value, true
+	}
+	if h.old != nil {
+		return h.old.Get(key)
+	}
+	var zero V
+	return zero, false
+}
 
-	for h.table[idx].isOccupied {
-		if !h.table[idx].isDeleted && h.table[idx].key == key {
-			h.table[idx].isDeleted = true
-			h.size--
-			return
+// Remove shifts the following entries of the cluster back by one slot
+// instead of leaving a tombstone, so lookups never scan dead slots. The
+// non-linear strategies cannot shift and leave a tombstone instead.
+func (h *HashTableOpen[K, V]) Remove(key K) {
+	if h.old != nil && h.old.find(key) >= 0 {
+		h.old.Remove(key)
+		h.version++
+	} else if !h.removeEntry(key) {
+		return
+	}
+	h.rehashStep()
+}
+
+// removeEntry deletes key from the current table and reports whether it
+// was there.
+func (h *HashTableOpen[K, V]) removeEntry(key K) bool {
+	idx := h.find(key)
+	if idx < 0 {
+		return false
+	}
+	if h.probing == GroupProbing {
+		h.removeGroupSlot(idx)
+		return true
+	}
+	if h.probing != LinearProbing {
+		h.table[idx] = HashEntry[K, V]{isOccupied: true, isDeleted: true}
+		h.size--
+		h.deleted++
+		h.version++
+		return true
+	}
+
+	next := (idx + 1) % h.capacity
+	for h.table[next].isOccupied && h.table[next].dist > 0 {
+		h.table[idx] = h.table[next]
+		h.table[idx].dist--
+		idx = next
+		next = (next + 1) % h.capacity
+	}
+	h.table[idx] = HashEntry[K, V]{}
+	h.size--
+	h.version++
+	return true
+}
+
+// ProbeStats reports the average and maximum probe distance of the stored
+// entries; a lookup inspects at most MaxDistance+1 slots. It completes a
+// pending incremental rehash first.
+func (h *HashTableOpen[K, V]) ProbeStats() ProbeStats {
+	h.finishRehash()
+	var stats ProbeStats
+	total := 0
+	for i := 0; i < h.capacity; i++ {
+		if !h.table[i].isOccupied || h.table[i].isDeleted {
+			continue
 		}
-		idx = (idx + 1) % h.capacity
-		if idx == startIdx {
-			break
+		total += h.table[i].dist
+		stats.MaxDistance = max(stats.MaxDistance, h.table[i].dist)
+	}
+	if h.size > 0 {
+		stats.AverageDistance = float64(total) / float64(h.size)
+	}
+	return stats
+}
+
+func (h *HashTableOpen[K, V]) Len() int {
+	if h.old != nil {
+		return h.size + h.old.size
+	}
+	return h.size
+}
+
+func (h *HashTableOpen[K, V]) IsEmpty() bool {
+	return h.Len() == 0
+}
+
+func (h *HashTableOpen[K, V]) Keys() []K {
+	keys := make([]K, 0, h.Len())
+	for k := range h.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (h *HashTableOpen[K, V]) Values() []V {
+	values := make([]V, 0, h.Len())
+	for _, v := range h.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+func (h *HashTableOpen[K, V]) Clear() {
+	h.resetTable(h.capacity)
+}
+
+// All yields key/value pairs in slot order, followed by the entries not
+// yet migrated by an incremental rehash.
+func (h *HashTableOpen[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		version := h.version
+		tables := [][]HashEntry[K, V]{h.table}
+		if h.old != nil {
+			tables = append(tables, h.old.table)
+		}
+		for _, table := range tables {
+			for i := range table {
+				if !table[i].isOccupied || table[i].isDeleted {
+					continue
+				}
+				if !yield(table[i].key, table[i].value) {
+					return
+				}
+				checkVersion(version, h.version)
+			}
 		}
 	}
 }
 
-func (h *HashTableOpen) Print() {
+func (h *HashTableOpen[K, V]) Print() {
+	h.finishRehash()
 	fmt.Println("HashTableOpen:")
 	for i := 0; i < h.capacity; i++ {
 		if h.table[i].isOccupied && !h.table[i].isDeleted {
-			fmt.Printf("[%d]: %d -> %d\n", i, h.table[i].key, h.table[i].value)
+			fmt.Printf("[%d]: %v -> %v\n", i, h.table[i].key, h.table[i].value)
 		}
 	}
 }
 
 // Binary Serialization
-func (h *HashTableOpen) Serialize(filename string) error {
+func (h *HashTableOpen[K, V]) Serialize(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for writing: %w", err)
 	}
 	defer file.Close()
 
-	if err := binary.Write(file, binary.LittleEndian, uint64(h.size)); err != nil {
+	return h.SerializeTo(file)
+}
+
+// SerializeTo writes the slot layout, so it completes a pending
+// incremental rehash first.
+func (h *HashTableOpen[K, V]) SerializeTo(w io.Writer) error {
+	h.finishRehash()
+	if err := binary.Write(w, binary.LittleEndian, uint64(h.size)); err != nil {
 		return err
 	}
-	if err := binary.Write(file, binary.LittleEndian, uint64(h.capacity)); err != nil {
+	if err := binary.Write(w, binary.LittleEndian, uint64(h.capacity)); err != nil {
 		return err
 	}
 
 	for i := 0; i < h.capacity; i++ {
-		if err := binary.Write(file, binary.LittleEndian, int32(h.table[i].key)); err != nil {
+		if err := writeValue(w, h.table[i].key); err != nil {
 			return err
 		}
-		if err := binary.Write(file, binary.LittleEndian, int32(h.table[i].value)); err != nil {
+		if err := writeValue(w, h.table[i].value); err != nil {
 			return err
 		}
-		if err := binary.Write(file, binary.LittleEndian, h.table[i].isOccupied); err != nil {
+		if err := binary.Write(w, binary.LittleEndian, h.table[i].isOccupied); err != nil {
 			return err
 		}
-		if err := binary.Write(file, binary.LittleEndian, h.table[i].isDeleted); err != nil {
+		if err := binary.Write(w, binary.LittleEndian, h.table[i].isDeleted); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (h *HashTableOpen) Deserialize(filename string) error {
+func (h *HashTableOpen[K, V]) Deserialize(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for reading: %w", err)
 	}
 	defer file.Close()
 
-	var size, capacity uint64
-	if err := binary.Read(file, binary.LittleEndian, &size); err != nil {
+	return h.DeserializeFrom(file)
+}
+
+func (h *HashTableOpen[K, V]) DeserializeFrom(r io.Reader) error {
+	var size, capacity uint64 // size is recomputed while re-inserting
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
 		return err
 	}
-	if err := binary.Read(file, binary.LittleEndian, &capacity); err != nil {
+	if err := binary.Read(r, binary.LittleEndian, &capacity); err != nil {
 		return err
 	}
 
-	h.size = int(size)
-	h.capacity = int(capacity)
-	h.table = make([]HashEntry, h.capacity)
+	if capacity == 0 {
+		return errors.New("invalid capacity in file")
+	}
 
-	for i := 0; i < h.capacity; i++ {
-		var key, value int32
-		if err := binary.Read(file, binary.LittleEndian, &key); err != nil {
+	// Slots are re-inserted rather than copied in place: the hasher may be
+	// seeded differently from the one that wrote the file.
+	h.resetTable(int(capacity))
+
+	for i := uint64(0); i < capacity; i++ {
+		var entry HashEntry[K, V]
+		var err error
+		if entry.key, err = readValue[K](r); err != nil {
 			return err
 		}
-		if err := binary.Read(file, binary.LittleEndian, &value); err != nil {
+		if entry.value, err = readValue[V](r); err != nil {
 			return err
 		}
-		if err := binary.Read(file, binary.LittleEndian, &h.table[i].isOccupied); err != nil {
+		if err := binary.Read(r, binary.LittleEndian, &entry.isOccupied); err != nil {
 			return err
 		}
-		if err := binary.Read(file, binary.LittleEndian, &h.table[i].isDeleted); err != nil {
+		if err := binary.Read(r, binary.LittleEndian, &entry.isDeleted); err != nil {
 			return err
 		}
-		h.table[i].key = int(key)
-		h.table[i].value = int(value)
+		if entry.isOccupied && !entry.isDeleted {
+			h.Insert(entry.key, entry.value)
+		}
 	}
 	return nil
 }
 
 // JSON Serialization
-type openEntry struct {
-	Key   int `json:"key"`
-	Value int `json:"value"`
+type openEntry[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
 }
 
-type hashTableOpenJSON struct {
-	Entries []openEntry `json:"entries"`
+type hashTableOpenJSON[K, V any] struct {
+	Entries []openEntry[K, V] `json:"entries"`
 }
 
-func (h *HashTableOpen) SerializeJSON(filename string) error {
+func (h *HashTableOpen[K, V]) SerializeJSON(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for writing: %w", err)
 	}
 	defer file.Close()
 
-	entries := make([]openEntry, 0, h.size)
-	for i := 0; i < h.capacity; i++ {
-		if h.table[i].isOccupied && !h.table[i].isDeleted {
-			entries = append(entries, openEntry{Key: h.table[i].key, Value: h.table[i].value})
-		}
+	entries := make([]openEntry[K, V], 0, h.Len())
+	for k, v := range h.All() {
+		entries = append(entries, openEntry[K, V]{Key: k, Value: v})
 	}
 
-	data := hashTableOpenJSON{Entries: entries}
+	data := hashTableOpenJSON[K, V]{Entries: entries}
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
 
-func (h *HashTableOpen) DeserializeJSON(filename string) error {
+func (h *HashTableOpen[K, V]) DeserializeJSON(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for reading: %w", err)
@@ -231,13 +683,9 @@ func (h *HashTableOpen) DeserializeJSON(filename string) error {
 	defer file.Close()
 
 	// Clear existing data
-	h.table = make([]HashEntry, h.capacity)
-	for i := 0; i < h.capacity; i++ {
-		h.table[i] = HashEntry{isOccupied: false, isDeleted: false}
-	}
-	h.size = 0
+	h.resetTable(h.capacity)
 
-	var data hashTableOpenJSON
+	var data hashTableOpenJSON[K, V]
 	decoder := json.NewDecoder(file)
 	if err := decoder.Decode(&data); err != nil {
 		return err
@@ -248,3 +696,35 @@ func (h *HashTableOpen) DeserializeJSON(filename string) error {
 	}
 	return nil
 }
+
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func nextPrime(n int) int {
+	if n <= 2 {
+		return 2
+	}
+	if n%2 == 0 {
+		n++
+	}
+	for ; ; n += 2 {
+		prime := true
+		for d := 3; d*d <= n; d += 2 {
+			if n%d == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			return n
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package datastructures
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Group probing for HashTableOpen (ProbeStrategy GroupProbing).
+//
+// Every slot has a control byte: ctrlEmpty, ctrlDeleted, or the low 7 bits
+// of the key's mixed hash when full. Slots are split into groups of
+// groupSize whose control bytes are loaded as one uint64, and the match
+// functions below find candidate slots in a group with a handful of word
+// operations (SWAR) instead of a loop. The group count is a power of two
+// and groups are visited in triangular order, which reaches all of them.
+
+const (
+	groupSize   = 8
+	ctrlEmpty   = 0x80
+	ctrlDeleted = 0xFE
+
+	lsbs = 0x0101010101010101
+	msbs = 0x8080808080808080
+)
+
+// groupCapacity rounds n up to a power-of-two number of groups.
+func groupCapacity(n int) int {
+	groups := 1
+	for groups*groupSize < n {
+		groups *= 2
+	}
+	return groups * groupSize
+}
+
+// matchByte sets the high bit of every byte of w equal to b. It can report
+// a false positive next to a true match, which the key comparison filters
+// out.
+func matchByte(w uint64, b byte) uint64 {
+	x := w ^ (lsbs * uint64(b))
+	return (x - lsbs) &^ x & msbs
+}
+
+// matchEmpty sets the high bit of every ctrlEmpty byte: bit 7 set and
+// bit 1 clear, which tells it apart from ctrlDeleted.
+func matchEmpty(w uint64) uint64 {
+	return w &^ (w << 6) & msbs
+}
+
+// matchEmptyOrDeleted sets the high bit of every byte that is not full.
+func matchEmptyOrDeleted(w uint64) uint64 {
+	return w &^ (w << 7) & msbs
+}
+
+// firstMatch turns a match mask into the index of its lowest byte.
+func firstMatch(m uint64) int {
+	return bits.TrailingZeros64(m) / 8
+}
+
+func (h *HashTableOpen[K, V]) groupWord(g int) uint64 {
+	return binary.LittleEndian.Uint64(h.ctrl[g*groupSize:])
+}
+
+// splitHash returns the first group to probe and the 7-bit fingerprint.
+func (h *HashTableOpen[K, V]) splitHash(key K) (int, byte) {
+	hv := mix64(h.hasher(key))
+	groups := h.capacity / groupSize
+	return int((hv >> 7) & uint64(groups-1)), byte(hv & 0x7F)
+}
+
+func (h *HashTableOpen[K, V]) findGroup(key K) int {
+	g, fp := h.splitHash(key)
+	mask := h.capacity/groupSize - 1
+	for i := 0; i <= mask; i++ {
+		w := h.groupWord(g)
+		for m := matchByte(w, fp); m != 0; m &= m - 1 {
+			idx := g*groupSize + firstMatch(m)
+			if h.ctrl[idx] == fp && h.equal(h.table[idx].key, key) {
+				return idx
+			}
+		}
+		if matchEmpty(w) != 0 {
+			return -1
+		}
+		g = (g + i + 1) & mask
+	}
+	return -1
+}
+
+// insertGroup keeps the load, tombstones included, at or below 7/8 so
+// every probe sequence ends at an empty slot.
+func (h *HashTableOpen[K, V]) insertGroup(key K, value V) {
+	if idx := h.findGroup(key); idx >= 0 {
+		h.table[idx].value = value
+		return
+	}
+	if (h.size+h.deleted+1)*8 > h.capacity*7 {
+		if h.deleted > h.size/2 {
+			h.rehash(h.capacity)
+		} else {
+			h.resize()
+		}
+	}
+
+	g, fp := h.splitHash(key)
+	mask := h.capacity/groupSize - 1
+	for i := 0; ; i++ {
+		if m := matchEmptyOrDeleted(h.groupWord(g)); m != 0 {
+			idx := g*groupSize + firstMatch(m)
+			if h.ctrl[idx] == ctrlDeleted {
+				h.deleted--
+			}
+			h.ctrl[idx] = fp
+			h.table[idx] = HashEntry[K, V]{key: key, value: value, isOccupied: true, dist: i}
+			h.size++
+			h.version++
+			return
+		}
+		g = (g + i + 1) & mask
+	}
+}
+
+// removeGroupSlot clears slot idx. A group that still has an empty slot
+// has never been full since the last rehash, so no probe sequence has
+// continued past it and the slot can become empty again; otherwise it
+// needs a tombstone.
+func (h *HashTableOpen[K, V]) removeGroupSlot(idx int) {
+	if matchEmpty(h.groupWord(idx/groupSize)) != 0 {
+		h.ctrl[idx] = ctrlEmpty
+		h.table[idx] = HashEntry[K, V]{}
+	} else {
+		h.ctrl[idx] = ctrlDeleted
+		h.table[idx] = HashEntry[K, V]{isOccupied: true, isDeleted: true}
+		h.deleted++
+	}
+	h.size--
+	h.version++
+}
@@ -0,0 +1,257 @@
+package datastructures
+
+import (
+	"fmt"
+	"iter"
+)
+
+type lfuEntry[K, V any] struct {
+	key   K
+	value V
+	freq  int
+	node  *DNode // node in the bucket of freq
+}
+
+// lfuBucket holds the slots of every entry used freq times.
+type lfuBucket struct {
+	node  *DNode            // node in freqs whose value is freq
+	items *DoublyLinkedList // slots, most recently used first
+}
+
+// LFUCache holds at most capacity entries and evicts the least frequently
+// used one, breaking ties by recency. It uses the O(1) layout of Shah,
+// Mitra and Matani: freqs lists the use counts that some entry has, in
+// ascending order, and each count has a bucket list of its entries. A hit
+// moves the entry into the bucket of the next count, which is either the
+// neighbouring node of freqs or a new node inserted after it, and the
+// victim is always at the back of the first bucket.
+type LFUCache[K, V any] struct {
+	capacity int
+	freqs    *DoublyLinkedList
+	buckets  *HashTableChain[int, *lfuBucket]
+	index    *HashTableChain[K, int]
+	slots    slotPool[lfuEntry[K, V]]
+	onEvict  func(key K, value V)
+	stats    CacheStats
+	version  int
+}
+
+// NewLFUCache returns a cache of int keys and values.
+func NewLFUCache(capacity int) *LFUCache[int, int] {
+	return NewLFUCacheOf[int, int](capacity, IntHasher, nil)
+}
+
+// NewLFUCacheOf builds a cache for any key type, like NewLRUCacheOf.
+func NewLFUCacheOf[K, V any](capacity int, hasher Hasher[K], equal func(a, b K) bool) *LFUCache[K, V] {
+	if capacity <= 0 {
+		panic("datastructures: LFUCache capacity must be positive")
+	}
+	return &LFUCache[K, V]{
+		capacity: capacity,
+		freqs:    NewDoublyLinkedList(),
+		buckets:  NewHashTableChainOf[int, *lfuBucket](0, IntHasher, nil),
+		index:    NewHashTableChainOf[K, int](capacity, hasher, equal),
+	}
+}
+
+// SetOnEvict registers fn to be called with every entry the cache evicts
+// to make room. Entries removed by Remove or Clear are not reported.
+func (c *LFUCache[K, V]) SetOnEvict(fn func(key K, value V)) {
+	c.onEvict = fn
+}
+
+func (c *LFUCache[K, V]) bucket(freq int) *lfuBucket {
+	b, _ := c.buckets.Get(freq)
+	return b
+}
+
+// dropBucket removes the bucket of freq once its last entry has left.
+func (c *LFUCache[K, V]) dropBucket(freq int, b *lfuBucket) {
+	c.freqs.RemoveNode(b.node)
+	c.buckets.Remove(freq)
+}
+
+// touch counts one more use of the entry in slot.
+func (c *LFUCache[K, V]) touch(slot int) {
+	entry := c.slots.at(slot)
+	b := c.bucket(entry.freq)
+	next, ok := c.buckets.Get(entry.freq + 1)
+	if !ok {
+		node, _ := c.freqs.InsertAfterNode(b.node, entry.freq+1)
+		next = &lfuBucket{node: node, items: NewDoublyLinkedList()}
+		c.buckets.Put(entry.freq+1, next)
+	}
+
+	b.items.RemoveNode(entry.node)
+	if b.items.IsEmpty() {
+		c.dropBucket(entry.freq, b)
+	}
+	entry.freq++
+	entry.node = next.items.PushFront(slot)
+	c.version++
+}
+
+// Get returns the value for key and counts a use of it.
+func (c *LFUCache[K, V]) Get(key K) (V, bool) {
+	slot, ok := c.index.Get(key)
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.touch(slot)
+	return c.slots.at(slot).value, true
+}
+
+// Peek returns the value for key without counting a use or a lookup.
+func (c *LFUCache[K, V]) Peek(key K) (V, bool) {
+	slot, ok := c.index.Get(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return c.slots.at(slot).value, true
+}
+
+func (c *LFUCache[K, V]) Contains(key K) bool {
+	_, ok := c.index.Get(key)
+	return ok
+}
+
+// Frequency returns how often key was used since it was added, or 0 if
+// it is not cached.
+func (c *LFUCache[K, V]) Frequency(key K) int {
+	slot, ok := c.index.Get(key)
+	if !ok {
+		return 0
+	}
+	return c.slots.at(slot).freq
+}
+
+// Put stores value under key and counts a use of it. A new key starts
+// with a count of one; adding it to a full cache first evicts the entry
+// with the lowest count.
+func (c *LFUCache[K, V]) Put(key K, value V) {
+	if slot, ok := c.index.Get(key); ok {
+		c.slots.at(slot).value = value
+		c.touch(slot)
+		return
+	}
+	if c.index.Len() >= c.capacity {
+		c.evict()
+	}
+
+	first := c.freqs.Front()
+	if first == nil || first.Value() != 1 {
+		first = c.freqs.PushFront(1)
+		c.buckets.Put(1, &lfuBucket{node: first, items: NewDoublyLinkedList()})
+	}
+	slot := c.slots.alloc(lfuEntry[K, V]{key: key, value: value, freq: 1})
+	c.slots.at(slot).node = c.bucket(1).items.PushFront(slot)
+	c.index.Put(key, slot)
+	c.version++
+}
+
+// evict drops the least recently used entry of the lowest count.
+func (c *LFUCache[K, V]) evict() {
+	b := c.bucket(c.freqs.Front().Value())
+	slot := b.items.Back().Value()
+	entry := *c.slots.at(slot)
+	c.removeSlot(slot)
+	c.stats.Evictions++
+	if c.onEvict != nil {
+		c.onEvict(entry.key, entry.value)
+	}
+}
+
+func (c *LFUCache[K, V]) removeSlot(slot int) {
+	entry := c.slots.at(slot)
+	b := c.bucket(entry.freq)
+	b.items.RemoveNode(entry.node)
+	if b.items.IsEmpty() {
+		c.dropBucket(entry.freq, b)
+	}
+	c.index.Remove(entry.key)
+	c.slots.release(slot)
+	c.version++
+}
+
+// Remove deletes key and reports whether it was cached.
+func (c *LFUCache[K, V]) Remove(key K) bool {
+	slot, ok := c.index.Get(key)
+	if !ok {
+		return false
+	}
+	c.removeSlot(slot)
+	return true
+}
+
+func (c *LFUCache[K, V]) Len() int {
+	return c.index.Len()
+}
+
+func (c *LFUCache[K, V]) Cap() int {
+	return c.capacity
+}
+
+func (c *LFUCache[K, V]) IsEmpty() bool {
+	return c.index.Len() == 0
+}
+
+// Clear drops every entry without calling onEvict and keeps the
+// statistics.
+func (c *LFUCache[K, V]) Clear() {
+	c.freqs.Clear()
+	c.buckets.Clear()
+	c.index.Clear()
+	c.slots.reset()
+	c.version++
+}
+
+func (c *LFUCache[K, V]) Stats() CacheStats {
+	return c.stats
+}
+
+func (c *LFUCache[K, V]) ResetStats() {
+	c.stats = CacheStats{}
+}
+
+// Keys returns the keys from most to least frequently used, so the last
+// one is the next to be evicted.
+func (c *LFUCache[K, V]) Keys() []K {
+	keys := make([]K, 0, c.index.Len())
+	for key := range c.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// All yields the entries in the order of Keys without counting a use.
+func (c *LFUCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		version := c.version
+		for f := c.freqs.Back(); f != nil; f = f.Prev() {
+			for n := c.bucket(f.Value()).items.Front(); n != nil; n = n.Next() {
+				entry := c.slots.at(n.Value())
+				if !yield(entry.key, entry.value) {
+					return
+				}
+				checkVersion(version, c.version)
+			}
+		}
+	}
+}
+
+func (c *LFUCache[K, V]) Print() {
+	fmt.Printf("LFUCache (%d/%d) [", c.index.Len(), c.capacity)
+	first := true
+	for key, value := range c.All() {
+		if !first {
+			fmt.Print(", ")
+		}
+		first = false
+		fmt.Printf("%v:%v", key, value)
+	}
+	fmt.Println("]")
+}
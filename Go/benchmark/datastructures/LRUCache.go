@@ -0,0 +1,247 @@
+package datastructures
+
+import (
+	"fmt"
+	"iter"
+)
+
+// CacheStats counts cache lookups. Peek is not counted.
+type CacheStats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+}
+
+// HitRate returns Hits/(Hits+Misses), or 0 before the first lookup.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// slotPool keeps cache entries in a slice so that the int lists of a
+// cache can refer to them by index. Released slots are reused before the
+// slice grows.
+type slotPool[E any] struct {
+	entries []E
+	free    []int
+}
+
+func (p *slotPool[E]) alloc(entry E) int {
+	if n := len(p.free); n > 0 {
+		slot := p.free[n-1]
+		p.free = p.free[:n-1]
+		p.entries[slot] = entry
+		return slot
+	}
+	p.entries = append(p.entries, entry)
+	return len(p.entries) - 1
+}
+
+func (p *slotPool[E]) at(slot int) *E {
+	return &p.entries[slot]
+}
+
+// release zeroes the slot so it does not keep its key and value alive.
+func (p *slotPool[E]) release(slot int) {
+	var zero E
+	p.entries[slot] = zero
+	p.free = append(p.free, slot)
+}
+
+func (p *slotPool[E]) reset() {
+	p.entries = nil
+	p.free = nil
+}
+
+type lruEntry[K, V any] struct {
+	key   K
+	value V
+	node  *DNode // node in order whose value is this entry's slot
+}
+
+// LRUCache holds at most capacity entries and evicts the least recently
+// used one to make room. The recency order is a DoublyLinkedList of slot
+// numbers, most recent first, and a HashTableChain maps each key to its
+// slot, so Get, Put and Remove are O(1): the list node of a slot is kept
+// alongside the entry and moved with MoveToFront.
+type LRUCache[K, V any] struct {
+	capacity int
+	order    *DoublyLinkedList
+	index    *HashTableChain[K, int]
+	slots    slotPool[lruEntry[K, V]]
+	onEvict  func(key K, value V)
+	stats    CacheStats
+	version  int
+}
+
+// NewLRUCache returns a cache of int keys and values.
+func NewLRUCache(capacity int) *LRUCache[int, int] {
+	return NewLRUCacheOf[int, int](capacity, IntHasher, nil)
+}
+
+// NewLRUCacheOf builds a cache for any key type. hasher and equal are
+// resolved as in NewHashTableChainOf. It panics if capacity is not
+// positive.
+func NewLRUCacheOf[K, V any](capacity int, hasher Hasher[K], equal func(a, b K) bool) *LRUCache[K, V] {
+	if capacity <= 0 {
+		panic("datastructures: LRUCache capacity must be positive")
+	}
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		order:    NewDoublyLinkedList(),
+		index:    NewHashTableChainOf[K, int](capacity, hasher, equal),
+	}
+}
+
+// SetOnEvict registers fn to be called with every entry the cache evicts
+// to make room. Entries removed by Remove or Clear are not reported.
+func (c *LRUCache[K, V]) SetOnEvict(fn func(key K, value V)) {
+	c.onEvict = fn
+}
+
+// Get returns the value for key and marks it most recently used.
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	slot, ok := c.index.Get(key)
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	entry := c.slots.at(slot)
+	c.order.MoveToFront(entry.node)
+	c.version++
+	return entry.value, true
+}
+
+// Peek returns the value for key without touching the recency order or
+// the statistics.
+func (c *LRUCache[K, V]) Peek(key K) (V, bool) {
+	slot, ok := c.index.Get(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return c.slots.at(slot).value, true
+}
+
+// Contains reports whether key is cached, like Peek.
+func (c *LRUCache[K, V]) Contains(key K) bool {
+	_, ok := c.index.Get(key)
+	return ok
+}
+
+// Put stores value under key and marks it most recently used. Adding a
+// new key to a full cache first evicts the least recently used entry.
+func (c *LRUCache[K, V]) Put(key K, value V) {
+	c.version++
+	if slot, ok := c.index.Get(key); ok {
+		entry := c.slots.at(slot)
+		entry.value = value
+		c.order.MoveToFront(entry.node)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		c.evict()
+	}
+
+	slot := c.slots.alloc(lruEntry[K, V]{key: key, value: value})
+	c.slots.at(slot).node = c.order.PushFront(slot)
+	c.index.Put(key, slot)
+}
+
+// evict drops the least recently used entry and reports it to onEvict.
+func (c *LRUCache[K, V]) evict() {
+	slot := c.order.Back().Value()
+	entry := *c.slots.at(slot)
+	c.removeSlot(slot)
+	c.stats.Evictions++
+	if c.onEvict != nil {
+		c.onEvict(entry.key, entry.value)
+	}
+}
+
+func (c *LRUCache[K, V]) removeSlot(slot int) {
+	entry := c.slots.at(slot)
+	c.order.RemoveNode(entry.node)
+	c.index.Remove(entry.key)
+	c.slots.release(slot)
+}
+
+// Remove deletes key and reports whether it was cached.
+func (c *LRUCache[K, V]) Remove(key K) bool {
+	slot, ok := c.index.Get(key)
+	if !ok {
+		return false
+	}
+	c.removeSlot(slot)
+	c.version++
+	return true
+}
+
+func (c *LRUCache[K, V]) Len() int {
+	return c.order.Len()
+}
+
+func (c *LRUCache[K, V]) Cap() int {
+	return c.capacity
+}
+
+func (c *LRUCache[K, V]) IsEmpty() bool {
+	return c.order.Len() == 0
+}
+
+// Clear drops every entry without calling onEvict. The statistics are
+// kept; see ResetStats.
+func (c *LRUCache[K, V]) Clear() {
+	c.order.Clear()
+	c.index.Clear()
+	c.slots.reset()
+	c.version++
+}
+
+func (c *LRUCache[K, V]) Stats() CacheStats {
+	return c.stats
+}
+
+func (c *LRUCache[K, V]) ResetStats() {
+	c.stats = CacheStats{}
+}
+
+// Keys returns the keys from most to least recently used.
+func (c *LRUCache[K, V]) Keys() []K {
+	keys := make([]K, 0, c.order.Len())
+	for n := c.order.Front(); n != nil; n = n.Next() {
+		keys = append(keys, c.slots.at(n.Value()).key)
+	}
+	return keys
+}
+
+// All yields the entries from most to least recently used without
+// promoting them.
+func (c *LRUCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		version := c.version
+		for n := c.order.Front(); n != nil; n = n.Next() {
+			entry := c.slots.at(n.Value())
+			if !yield(entry.key, entry.value) {
+				return
+			}
+			checkVersion(version, c.version)
+		}
+	}
+}
+
+func (c *LRUCache[K, V]) Print() {
+	fmt.Printf("LRUCache (%d/%d) [", c.order.Len(), c.capacity)
+	for n := c.order.Front(); n != nil; n = n.Next() {
+		entry := c.slots.at(n.Value())
+		fmt.Printf("%v:%v", entry.key, entry.value)
+		if n.Next() != nil {
+			fmt.Print(", ")
+		}
+	}
+	fmt.Println("]")
+}
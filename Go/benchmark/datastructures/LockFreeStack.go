@@ -0,0 +1,88 @@
+package datastructures
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+type lockFreeStackNode struct {
+	data int
+	next *lockFreeStackNode
+}
+
+// LockFreeStack is a Treiber stack: a LIFO of ints that any number of
+// goroutines can use at once. Push and TryPop retry a CompareAndSwap on
+// the top pointer until no other goroutine changed it in between.
+//
+// A node is never modified once pushed and every Push allocates a new one.
+// A popped node cannot come back while some TryPop still holds a pointer
+// to it, because the garbage collector keeps it alive and no Push can
+// reuse it, so a successful CAS always means top did not change and the
+// ABA problem cannot occur.
+type LockFreeStack struct {
+	top  atomic.Pointer[lockFreeStackNode]
+	size atomic.Int64
+}
+
+func NewLockFreeStack() *LockFreeStack {
+	return &LockFreeStack{}
+}
+
+func (s *LockFreeStack) Push(value int) {
+	node := &lockFreeStackNode{data: value}
+	for {
+		node.next = s.top.Load()
+		if s.top.CompareAndSwap(node.next, node) {
+			s.size.Add(1)
+			return
+		}
+	}
+}
+
+// TryPop removes and returns the top element, or reports false if the
+// stack is empty.
+func (s *LockFreeStack) TryPop() (int, bool) {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			return 0, false
+		}
+		if s.top.CompareAndSwap(top, top.next) {
+			s.size.Add(-1)
+			return top.data, true
+		}
+	}
+}
+
+// Pop removes the top element, if any, like MyStack.Pop.
+func (s *LockFreeStack) Pop() {
+	s.TryPop()
+}
+
+func (s *LockFreeStack) Peek() (int, error) {
+	top := s.top.Load()
+	if top == nil {
+		return 0, errors.New("stack empty")
+	}
+	return top.data, nil
+}
+
+// Len returns the number of elements. Under concurrent use it is only a
+// snapshot, and a Pop can be counted before the matching Push.
+func (s *LockFreeStack) Len() int {
+	return max(int(s.size.Load()), 0)
+}
+
+func (s *LockFreeStack) IsEmpty() bool {
+	return s.top.Load() == nil
+}
+
+// Values returns the elements from top to bottom as of one point in time:
+// the nodes below a loaded top never change.
+func (s *LockFreeStack) Values() []int {
+	values := make([]int, 0, s.Len())
+	for curr := s.top.Load(); curr != nil; curr = curr.next {
+		values = append(values, curr.data)
+	}
+	return values
+}
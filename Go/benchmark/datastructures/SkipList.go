@@ -0,0 +1,412 @@
+package datastructures
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand/v2"
+	"os"
+)
+
+// skipListMaxLevel bounds the tower height. With p = 1/4 it covers far
+// more than 2^32 keys.
+const skipListMaxLevel = 32
+
+type skipNode[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*skipNode[K, V] // next[i] is the following node on level i
+}
+
+// SkipList is a sorted map from K to V with the same surface as AVLTree;
+// with V = struct{} it is an ordered set. Every key is on level 0 and each
+// node reaches one level higher with probability 1/4, which gives expected
+// O(log n) search, insert and remove without any rebalancing.
+//
+// The levels come from the list's own generator, so SetSeed makes the
+// shape, and with it every benchmark and test run, reproducible.
+type SkipList[K cmp.Ordered, V any] struct {
+	head    *skipNode[K, V] // sentinel with a tower of every level
+	level   int             // number of levels in use
+	size    int
+	rng     *rand.Rand
+	version int
+}
+
+func NewSkipList() *SkipList[int, struct{}] {
+	return NewSkipListOf[int, struct{}]()
+}
+
+// NewSkipListOf returns an empty list with a randomly seeded level
+// generator.
+func NewSkipListOf[K cmp.Ordered, V any]() *SkipList[K, V] {
+	s := &SkipList[K, V]{
+		head:  &skipNode[K, V]{next: make([]*skipNode[K, V], skipListMaxLevel)},
+		level: 1,
+	}
+	s.SetSeed(rand.Uint64())
+	return s
+}
+
+// SetSeed restarts the level generator from seed. Lists that get the same
+// seed and the same operations end up with the same shape.
+func (s *SkipList[K, V]) SetSeed(seed uint64) {
+	s.rng = rand.New(rand.NewPCG(seed, seed))
+}
+
+// storesValues reports whether V carries data, as for AVLTree.
+func (s *SkipList[K, V]) storesValues() bool {
+	_, isSet := any(*new(V)).(struct{})
+	return !isSet
+}
+
+func (s *SkipList[K, V]) randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && s.rng.Uint32()&3 == 0 {
+		level++
+	}
+	return level
+}
+
+// findPredecessors fills update with the last node before key on every
+// level in use and returns the level 0 successor, which holds key if
+// present.
+func (s *SkipList[K, V]) findPredecessors(key K, update *[skipListMaxLevel]*skipNode[K, V]) *skipNode[K, V] {
+	curr := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for curr.next[i] != nil && curr.next[i].key < key {
+			curr = curr.next[i]
+		}
+		update[i] = curr
+	}
+	return curr.next[0]
+}
+
+func (s *SkipList[K, V]) findNode(key K) *skipNode[K, V] {
+	curr := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for curr.next[i] != nil && curr.next[i].key < key {
+			curr = curr.next[i]
+		}
+	}
+	if next := curr.next[0]; next != nil && next.key == key {
+		return next
+	}
+	return nil
+}
+
+// seek returns the first node with a key >= key, or > key if not
+// inclusive.
+func (s *SkipList[K, V]) seek(key K, inclusive bool) *skipNode[K, V] {
+	curr := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for curr.next[i] != nil && (curr.next[i].key < key || (!inclusive && curr.next[i].key == key)) {
+			curr = curr.next[i]
+		}
+	}
+	return curr.next[0]
+}
+
+func (s *SkipList[K, V]) put(key K, value V, replace bool) {
+	var update [skipListMaxLevel]*skipNode[K, V]
+	if node := s.findPredecessors(key, &update); node != nil && node.key == key {
+		if replace {
+			node.value = value
+		}
+		return
+	}
+
+	level := s.randomLevel()
+	for i := s.level; i < level; i++ {
+		update[i] = s.head
+	}
+	s.level = max(s.level, level)
+
+	node := &skipNode[K, V]{key: key, value: value, next: make([]*skipNode[K, V], level)}
+	for i := 0; i < level; i++ {
+		node.next[i] = update[i].next[i]
+		update[i].next[i] = node
+	}
+	s.size++
+	s.version++
+}
+
+// Insert adds key with a zero value. An existing key keeps its value.
+func (s *SkipList[K, V]) Insert(key K) {
+	s.put(key, *new(V), false)
+}
+
+// Put stores value under key, replacing the value of an existing key.
+func (s *SkipList[K, V]) Put(key K, value V) {
+	s.put(key, value, true)
+}
+
+func (s *SkipList[K, V]) Get(key K) (V, bool) {
+	if node := s.findNode(key); node != nil {
+		return node.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (s *SkipList[K, V]) Find(key K) bool {
+	return s.findNode(key) != nil
+}
+
+func (s *SkipList[K, V]) Remove(key K) {
+	s.Delete(key)
+}
+
+// Delete removes key and reports whether it was present.
+func (s *SkipList[K, V]) Delete(key K) bool {
+	var update [skipListMaxLevel]*skipNode[K, V]
+	node := s.findPredecessors(key, &update)
+	if node == nil || node.key != key {
+		return false
+	}
+	for i := range node.next {
+		update[i].next[i] = node.next[i]
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.size--
+	s.version++
+	return true
+}
+
+func (s *SkipList[K, V]) Len() int {
+	return s.size
+}
+
+func (s *SkipList[K, V]) IsEmpty() bool {
+	return s.size == 0
+}
+
+func (s *SkipList[K, V]) Clear() {
+	clear(s.head.next)
+	s.level = 1
+	s.size = 0
+	s.version++
+}
+
+// Level returns the number of levels in use.
+func (s *SkipList[K, V]) Level() int {
+	return s.level
+}
+
+// Values returns the keys in ascending order, like AVLTree.Values.
+func (s *SkipList[K, V]) Values() []K {
+	keys := make([]K, 0, s.size)
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		keys = append(keys, n.key)
+	}
+	return keys
+}
+
+// ascend yields the entries from n on while within holds for their key.
+// If the loop body changed the list, it resumes at the first key after the
+// one just yielded, so unlike the other iterators of the package it lets
+// the body insert and remove keys: those inserted ahead are yielded, those
+// removed ahead are not.
+func (s *SkipList[K, V]) ascend(n *skipNode[K, V], within func(K) bool, yield func(K, V) bool) {
+	for n != nil && within(n.key) {
+		version := s.version
+		if !yield(n.key, n.value) {
+			return
+		}
+		if s.version == version {
+			n = n.next[0]
+		} else {
+			n = s.seek(n.key, false)
+		}
+	}
+}
+
+// All yields key/value pairs in ascending key order. The loop body may
+// modify the list; see ascend.
+func (s *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.ascend(s.head.next[0], func(K) bool { return true }, yield)
+	}
+}
+
+// Range yields the key/value pairs with lo <= key <= hi in ascending order
+// after an O(log n) search for lo.
+func (s *SkipList[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.ascend(s.seek(lo, true), func(key K) bool { return key <= hi }, yield)
+	}
+}
+
+func (s *SkipList[K, V]) Min() (K, bool) {
+	if first := s.head.next[0]; first != nil {
+		return first.key, true
+	}
+	var zero K
+	return zero, false
+}
+
+func (s *SkipList[K, V]) Max() (K, bool) {
+	curr := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for curr.next[i] != nil {
+			curr = curr.next[i]
+		}
+	}
+	if curr == s.head {
+		var zero K
+		return zero, false
+	}
+	return curr.key, true
+}
+
+// Floor returns the largest key <= key.
+func (s *SkipList[K, V]) Floor(key K) (K, bool) {
+	curr := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for curr.next[i] != nil && curr.next[i].key <= key {
+			curr = curr.next[i]
+		}
+	}
+	if curr == s.head {
+		var zero K
+		return zero, false
+	}
+	return curr.key, true
+}
+
+// Ceiling returns the smallest key >= key.
+func (s *SkipList[K, V]) Ceiling(key K) (K, bool) {
+	if node := s.seek(key, true); node != nil {
+		return node.key, true
+	}
+	var zero K
+	return zero, false
+}
+
+func (s *SkipList[K, V]) Print() {
+	fmt.Print("SkipList: ")
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		if s.storesValues() {
+			fmt.Print(n.key, ":", n.value, " ")
+		} else {
+			fmt.Print(n.key, " ")
+		}
+	}
+	fmt.Println()
+}
+
+// Binary Serialization
+func (s *SkipList[K, V]) Serialize(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	return s.SerializeTo(file)
+}
+
+// SerializeTo writes the count followed by key/value pairs in ascending
+// order. The levels are not stored; a loaded list draws new ones.
+func (s *SkipList[K, V]) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(s.size)); err != nil {
+		return err
+	}
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		if err := writeValue(w, n.key); err != nil {
+			return err
+		}
+		if err := writeValue(w, n.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SkipList[K, V]) Deserialize(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	return s.DeserializeFrom(file)
+}
+
+func (s *SkipList[K, V]) DeserializeFrom(r io.Reader) error {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return err
+	}
+
+	s.Clear()
+	for i := uint64(0); i < count; i++ {
+		key, err := readValue[K](r)
+		if err != nil {
+			return err
+		}
+		value, err := readValue[V](r)
+		if err != nil {
+			return err
+		}
+		s.Put(key, value)
+	}
+	return nil
+}
+
+// JSON Serialization
+type skipListJSON[K cmp.Ordered, V any] struct {
+	Keys   []K `json:"keys"`
+	Values []V `json:"values,omitempty"` // left out for sets
+}
+
+func (s *SkipList[K, V]) SerializeJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	data := skipListJSON[K, V]{Keys: s.Values()}
+	if s.storesValues() {
+		data.Values = make([]V, 0, s.size)
+		for n := s.head.next[0]; n != nil; n = n.next[0] {
+			data.Values = append(data.Values, n.value)
+		}
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (s *SkipList[K, V]) DeserializeJSON(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	var data skipListJSON[K, V]
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+	if len(data.Values) != 0 && len(data.Values) != len(data.Keys) {
+		return fmt.Errorf("skip list json: %d keys but %d values", len(data.Keys), len(data.Values))
+	}
+
+	s.Clear()
+	for i, key := range data.Keys {
+		if len(data.Values) != 0 {
+			s.Put(key, data.Values[i])
+		} else {
+			s.Insert(key)
+		}
+	}
+	return nil
+}
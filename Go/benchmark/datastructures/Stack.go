@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 )
 
@@ -15,39 +17,128 @@ type StackNode struct {
 
 type MyStack struct {
 	topNode *StackNode
+	size    int
+	version int
+	// items holds the elements instead of the node chain when the stack
+	// was built by NewMyStackOnDeque; the top is the back of the deque.
+	items *Deque[int]
 }
 
 func NewMyStack() *MyStack {
 	return &MyStack{topNode: nil}
 }
 
+// NewMyStackOnDeque returns a stack stored in a ring-buffer Deque, so Push
+// does not allocate a node. It behaves and serializes exactly like a
+// NewMyStack stack.
+func NewMyStackOnDeque() *MyStack {
+	return &MyStack{items: NewDeque()}
+}
+
 func (s *MyStack) Push(value int) {
+	if s.items != nil {
+		s.items.PushBack(value)
+		return
+	}
 	newNode := &StackNode{data: value, next: s.topNode}
 	s.topNode = newNode
+	s.size++
+	s.version++
 }
 
 func (s *MyStack) Pop() {
+	if s.items != nil {
+		s.items.PopBack()
+		return
+	}
 	if s.topNode != nil {
 		s.topNode = s.topNode.next
+		s.size--
+		s.version++
 	}
 }
 
 func (s *MyStack) Peek() (int, error) {
+	if s.items != nil {
+		if s.items.IsEmpty() {
+			return 0, errors.New("stack empty")
+		}
+		return s.items.Back()
+	}
 	if s.topNode == nil {
 		return 0, errors.New("stack empty")
 	}
 	return s.topNode.data, nil
 }
 
+func (s *MyStack) Len() int {
+	if s.items != nil {
+		return s.items.Len()
+	}
+	return s.size
+}
+
+func (s *MyStack) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+func (s *MyStack) Clear() {
+	if s.items != nil {
+		s.items.Clear()
+		return
+	}
+	s.topNode = nil
+	s.size = 0
+	s.version++
+}
+
+// Values returns the elements from top to bottom.
+func (s *MyStack) Values() []int {
+	values := make([]int, 0, s.Len())
+	for v := range s.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// At returns the element index positions below the top.
+func (s *MyStack) At(index int) (int, error) {
+	if index < 0 || index >= s.Len() {
+		return 0, errors.New("index out of bounds")
+	}
+	if s.items != nil {
+		return s.items.At(s.items.Len() - 1 - index)
+	}
+	curr := s.topNode
+	for i := 0; i < index; i++ {
+		curr = curr.next
+	}
+	return curr.data, nil
+}
+
+// All yields the elements from top to bottom.
+func (s *MyStack) All() iter.Seq[int] {
+	if s.items != nil {
+		return s.items.Backward()
+	}
+	return func(yield func(int) bool) {
+		version := s.version
+		for curr := s.topNode; curr != nil; curr = curr.next {
+			if !yield(curr.data) {
+				return
+			}
+			checkVersion(version, s.version)
+		}
+	}
+}
+
 func (s *MyStack) Print() {
 	fmt.Print("Stack [")
-	curr := s.topNode
-	for curr != nil {
-		fmt.Print(curr.data)
-		if curr.next != nil {
+	for i, v := range s.Values() {
+		if i > 0 {
 			fmt.Print(" -> ")
 		}
-		curr = curr.next
+		fmt.Print(v)
 	}
 	fmt.Println("]")
 }
@@ -60,23 +151,18 @@ func (s *MyStack) Serialize(filename string) error {
 	}
 	defer file.Close()
 
-	count := uint64(0)
-	curr := s.topNode
-	for curr != nil {
-		count++
-		curr = curr.next
-	}
+	return s.SerializeTo(file)
+}
 
-	if err := binary.Write(file, binary.LittleEndian, count); err != nil {
+func (s *MyStack) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(s.Len())); err != nil {
 		return err
 	}
 
-	curr = s.topNode
-	for curr != nil {
-		if err := binary.Write(file, binary.LittleEndian, int32(curr.data)); err != nil {
+	for v := range s.All() {
+		if err := binary.Write(w, binary.LittleEndian, int32(v)); err != nil {
 			return err
 		}
-		curr = curr.next
 	}
 	return nil
 }
@@ -88,22 +174,29 @@ func (s *MyStack) Deserialize(filename string) error {
 	}
 	defer file.Close()
 
-	// Clear existing stack
-	for s.topNode != nil {
-		s.Pop()
-	}
+	return s.DeserializeFrom(file)
+}
+
+func (s *MyStack) DeserializeFrom(r io.Reader) error {
+	s.Clear()
 
 	var count uint64
-	if err := binary.Read(file, binary.LittleEndian, &count); err != nil {
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
 		return err
 	}
 
+	values := make([]int, 0, min(count, 1<<16))
 	for i := uint64(0); i < count; i++ {
 		var value int32
-		if err := binary.Read(file, binary.LittleEndian, &value); err != nil {
+		if err := binary.Read(r, binary.LittleEndian, &value); err != nil {
 			return err
 		}
-		s.Push(int(value))
+		values = append(values, int(value))
+	}
+
+	// Values are stored top first, so push them back bottom first
+	for i := len(values) - 1; i >= 0; i-- {
+		s.Push(values[i])
 	}
 	return nil
 }
@@ -120,14 +213,7 @@ func (s *MyStack) SerializeJSON(filename string) error {
 	}
 	defer file.Close()
 
-	data := make([]int, 0)
-	curr := s.topNode
-	for curr != nil {
-		data = append(data, curr.data)
-		curr = curr.next
-	}
-
-	stackData := stackJSON{Data: data}
+	stackData := stackJSON{Data: s.Values()}
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(stackData)
@@ -140,10 +226,7 @@ func (s *MyStack) DeserializeJSON(filename string) error {
 	}
 	defer file.Close()
 
-	// Clear existing stack
-	for s.topNode != nil {
-		s.Pop()
-	}
+	s.Clear()
 
 	var stackData stackJSON
 	decoder := json.NewDecoder(file)
@@ -151,8 +234,8 @@ func (s *MyStack) DeserializeJSON(filename string) error {
 		return err
 	}
 
-	for _, value := range stackData.Data {
-		s.Push(value)
+	for i := len(stackData.Data) - 1; i >= 0; i-- {
+		s.Push(stackData.Data[i])
 	}
 	return nil
 }
@@ -1,43 +1,78 @@
 package datastructures
 
 import (
+	"cmp"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 )
 
-type AVLNode struct {
-	key    int
-	left   *AVLNode
-	right  *AVLNode
+type AVLNode[K cmp.Ordered, V any] struct {
+	key    K
+	value  V
+	left   *AVLNode[K, V]
+	right  *AVLNode[K, V]
 	height int
+	size   int // number of nodes in this subtree
 }
 
-type AVLTree struct {
-	root *AVLNode
+// AVLTree is a sorted map from K to V. With V = struct{} it is the ordered
+// set the package started with, which is what NewAVLTree returns.
+type AVLTree[K cmp.Ordered, V any] struct {
+	root    *AVLNode[K, V]
+	version int
+	stats   TreeStats
 }
 
-func NewAVLTree() *AVLTree {
-	return &AVLTree{root: nil}
+// TreeStats counts the rebalancing work of a balanced tree. An AVL tree
+// only rotates; a red-black tree mostly recolors.
+type TreeStats struct {
+	Rotations   int
+	Recolorings int
 }
 
-func (t *AVLTree) height(n *AVLNode) int {
+func NewAVLTree() *AVLTree[int, struct{}] {
+	return NewAVLTreeOf[int, struct{}]()
+}
+
+func NewAVLTreeOf[K cmp.Ordered, V any]() *AVLTree[K, V] {
+	return &AVLTree[K, V]{root: nil}
+}
+
+// storesValues reports whether V carries data, i.e. the tree is used as a
+// map rather than a set.
+func (t *AVLTree[K, V]) storesValues() bool {
+	_, isSet := any(*new(V)).(struct{})
+	return !isSet
+}
+
+func (t *AVLTree[K, V]) height(n *AVLNode[K, V]) int {
 	if n == nil {
 		return 0
 	}
 	return n.height
 }
 
-func (t *AVLTree) max(a, b int) int {
+func (t *AVLTree[K, V]) sizeOf(n *AVLNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (t *AVLTree[K, V]) max(a, b int) int {
 	if a > b {
 		return a
 	}
 	return b
 }
 
-func (t *AVLTree) rightRotate(y *AVLNode) *AVLNode {
+func (t *AVLTree[K, V]) rightRotate(y *AVLNode[K, V]) *AVLNode[K, V] {
+	t.stats.Rotations++
 	x := y.left
 	T2 := x.right
 
@@ -46,11 +81,14 @@ func (t *AVLTree) rightRotate(y *AVLNode) *AVLNode {
 
 	y.height = t.max(t.height(y.left), t.height(y.right)) + 1
 	x.height = t.max(t.height(x.left), t.height(x.right)) + 1
+	y.size = t.sizeOf(y.left) + t.sizeOf(y.right) + 1
+	x.size = t.sizeOf(x.left) + t.sizeOf(x.right) + 1
 
 	return x
 }
 
-func (t *AVLTree) leftRotate(x *AVLNode) *AVLNode {
+func (t *AVLTree[K, V]) leftRotate(x *AVLNode[K, V]) *AVLNode[K, V] {
+	t.stats.Rotations++
 	y := x.right
 	T2 := y.left
 
@@ -59,31 +97,34 @@ func (t *AVLTree) leftRotate(x *AVLNode) *AVLNode {
 
 	x.height = t.max(t.height(x.left), t.height(x.right)) + 1
 	y.height = t.max(t.height(y.left), t.height(y.right)) + 1
+	x.size = t.sizeOf(x.left) + t.sizeOf(x.right) + 1
+	y.size = t.sizeOf(y.left) + t.sizeOf(y.right) + 1
 
 	return y
 }
 
-func (t *AVLTree) getBalance(n *AVLNode) int {
+func (t *AVLTree[K, V]) getBalance(n *AVLNode[K, V]) int {
 	if n == nil {
 		return 0
 	}
 	return t.height(n.left) - t.height(n.right)
 }
 
-func (t *AVLTree) insertNode(node *AVLNode, key int) *AVLNode {
+func (t *AVLTree[K, V]) insertNode(node *AVLNode[K, V], key K, value V) *AVLNode[K, V] {
 	if node == nil {
-		return &AVLNode{key: key, left: nil, right: nil, height: 1}
+		return &AVLNode[K, V]{key: key, value: value, left: nil, right: nil, height: 1, size: 1}
 	}
 
 	if key < node.key {
-		node.left = t.insertNode(node.left, key)
+		node.left = t.insertNode(node.left, key, value)
 	} else if key > node.key {
-		node.right = t.insertNode(node.right, key)
+		node.right = t.insertNode(node.right, key, value)
 	} else {
 		return node
 	}
 
 	node.height = 1 + t.max(t.height(node.left), t.height(node.right))
+	node.size = 1 + t.sizeOf(node.left) + t.sizeOf(node.right)
 	balance := t.getBalance(node)
 
 	// LL Case
@@ -111,7 +152,7 @@ func (t *AVLTree) insertNode(node *AVLNode, key int) *AVLNode {
 	return node
 }
 
-func (t *AVLTree) minValueNode(node *AVLNode) *AVLNode {
+func (t *AVLTree[K, V]) minValueNode(node *AVLNode[K, V]) *AVLNode[K, V] {
 	current := node
 	for current.left != nil {
 		current = current.left
@@ -119,7 +160,7 @@ func (t *AVLTree) minValueNode(node *AVLNode) *AVLNode {
 	return current
 }
 
-func (t *AVLTree) deleteNode(root *AVLNode, key int) *AVLNode {
+func (t *AVLTree[K, V]) deleteNode(root *AVLNode[K, V], key K) *AVLNode[K, V] {
 	if root == nil {
 		return root
 	}
@@ -130,7 +171,7 @@ func (t *AVLTree) deleteNode(root *AVLNode, key int) *AVLNode {
 		root.right = t.deleteNode(root.right, key)
 	} else {
 		if root.left == nil || root.right == nil {
-			var temp *AVLNode
+			var temp *AVLNode[K, V]
 			if root.left != nil {
 				temp = root.left
 			} else {
@@ -147,6 +188,7 @@ func (t *AVLTree) deleteNode(root *AVLNode, key int) *AVLNode {
 		} else {
 			temp := t.minValueNode(root.right)
 			root.key = temp.key
+			root.value = temp.value
 			root.right = t.deleteNode(root.right, temp.key)
 		}
 	}
@@ -156,6 +198,7 @@ func (t *AVLTree) deleteNode(root *AVLNode, key int) *AVLNode {
 	}
 
 	root.height = 1 + t.max(t.height(root.left), t.height(root.right))
+	root.size = 1 + t.sizeOf(root.left) + t.sizeOf(root.right)
 	balance := t.getBalance(root)
 
 	if balance > 1 && t.getBalance(root.left) >= 0 {
@@ -179,15 +222,182 @@ func (t *AVLTree) deleteNode(root *AVLNode, key int) *AVLNode {
 	return root
 }
 
-func (t *AVLTree) inOrder(root *AVLNode) {
+// Join-based set algebra (Blelloch, Ferizovic and Sun). Split, Join,
+// Union, Intersection and Difference are built from joinNodes, which links
+// two trees around a middle node in time proportional to their height
+// difference, and splitNode. The node helpers reuse the nodes they are
+// given, so their inputs are consumed, except for the second tree of the
+// set operations: that one is only read, and unionNodes copies the nodes
+// it takes from it.
+
+func (t *AVLTree[K, V]) update(n *AVLNode[K, V]) {
+	n.height = 1 + t.max(t.height(n.left), t.height(n.right))
+	n.size = 1 + t.sizeOf(n.left) + t.sizeOf(n.right)
+}
+
+// joinNodes returns a balanced tree holding left, mid and right, where all
+// keys in left are smaller than mid.key and all keys in right are larger.
+func (t *AVLTree[K, V]) joinNodes(left, mid, right *AVLNode[K, V]) *AVLNode[K, V] {
+	if t.height(left) > t.height(right)+1 {
+		return t.joinRight(left, mid, right)
+	}
+	if t.height(right) > t.height(left)+1 {
+		return t.joinLeft(left, mid, right)
+	}
+	mid.left = left
+	mid.right = right
+	t.update(mid)
+	return mid
+}
+
+// joinRight descends the right spine of the taller left tree until it
+// finds a subtree of about right's height.
+func (t *AVLTree[K, V]) joinRight(left, mid, right *AVLNode[K, V]) *AVLNode[K, V] {
+	c := left.right
+	if t.height(c) <= t.height(right)+1 {
+		mid.left = c
+		mid.right = right
+		t.update(mid)
+		if t.height(mid) <= t.height(left.left)+1 {
+			left.right = mid
+			t.update(left)
+			return left
+		}
+		left.right = t.rightRotate(mid)
+		t.update(left)
+		return t.leftRotate(left)
+	}
+
+	left.right = t.joinRight(c, mid, right)
+	t.update(left)
+	if t.height(left.right) <= t.height(left.left)+1 {
+		return left
+	}
+	return t.leftRotate(left)
+}
+
+func (t *AVLTree[K, V]) joinLeft(left, mid, right *AVLNode[K, V]) *AVLNode[K, V] {
+	c := right.left
+	if t.height(c) <= t.height(left)+1 {
+		mid.left = left
+		mid.right = c
+		t.update(mid)
+		if t.height(mid) <= t.height(right.right)+1 {
+			right.left = mid
+			t.update(right)
+			return right
+		}
+		right.left = t.leftRotate(mid)
+		t.update(right)
+		return t.rightRotate(right)
+	}
+
+	right.left = t.joinLeft(left, mid, c)
+	t.update(right)
+	if t.height(right.left) <= t.height(right.right)+1 {
+		return right
+	}
+	return t.rightRotate(right)
+}
+
+// splitNode splits n into the keys below key and the keys above it. The
+// node holding key, if any, is returned detached in the middle.
+func (t *AVLTree[K, V]) splitNode(n *AVLNode[K, V], key K) (*AVLNode[K, V], *AVLNode[K, V], *AVLNode[K, V]) {
+	if n == nil {
+		return nil, nil, nil
+	}
+	left, right := n.left, n.right
+	if key == n.key {
+		n.left = nil
+		n.right = nil
+		t.update(n)
+		return left, n, right
+	}
+	if key < n.key {
+		ll, found, lr := t.splitNode(left, key)
+		return ll, found, t.joinNodes(lr, n, right)
+	}
+	rl, found, rr := t.splitNode(right, key)
+	return t.joinNodes(left, n, rl), found, rr
+}
+
+// splitLast detaches the largest node of n.
+func (t *AVLTree[K, V]) splitLast(n *AVLNode[K, V]) (*AVLNode[K, V], *AVLNode[K, V]) {
+	if n.right == nil {
+		rest := n.left
+		n.left = nil
+		t.update(n)
+		return rest, n
+	}
+	rest, last := t.splitLast(n.right)
+	return t.joinNodes(n.left, n, rest), last
+}
+
+// join2 concatenates two trees whose key ranges do not overlap.
+func (t *AVLTree[K, V]) join2(left, right *AVLNode[K, V]) *AVLNode[K, V] {
+	if left == nil {
+		return right
+	}
+	rest, last := t.splitLast(left)
+	return t.joinNodes(rest, last, right)
+}
+
+// unionNodes merges copies of the entries of b into a. For keys present
+// in both, a's node wins.
+func (t *AVLTree[K, V]) unionNodes(a, b *AVLNode[K, V]) *AVLNode[K, V] {
+	if a == nil {
+		return t.cloneNodes(b)
+	}
+	if b == nil {
+		return a
+	}
+	bl, br := b.left, b.right
+	al, found, ar := t.splitNode(a, b.key)
+	mid := found
+	if mid == nil {
+		mid = &AVLNode[K, V]{key: b.key, value: b.value, height: 1, size: 1}
+	}
+	left := t.unionNodes(al, bl)
+	right := t.unionNodes(ar, br)
+	return t.joinNodes(left, mid, right)
+}
+
+func (t *AVLTree[K, V]) intersectNodes(a, b *AVLNode[K, V]) *AVLNode[K, V] {
+	if a == nil || b == nil {
+		return nil
+	}
+	bl, br := b.left, b.right
+	al, found, ar := t.splitNode(a, b.key)
+	left := t.intersectNodes(al, bl)
+	right := t.intersectNodes(ar, br)
+	if found != nil {
+		return t.joinNodes(left, found, right)
+	}
+	return t.join2(left, right)
+}
+
+func (t *AVLTree[K, V]) differenceNodes(a, b *AVLNode[K, V]) *AVLNode[K, V] {
+	if a == nil || b == nil {
+		return a
+	}
+	bl, br := b.left, b.right
+	al, _, ar := t.splitNode(a, b.key)
+	return t.join2(t.differenceNodes(al, bl), t.differenceNodes(ar, br))
+}
+
+func (t *AVLTree[K, V]) inOrder(root *AVLNode[K, V]) {
 	if root != nil {
 		t.inOrder(root.left)
-		fmt.Print(root.key, " ")
+		if t.storesValues() {
+			fmt.Print(root.key, ":", root.value, " ")
+		} else {
+			fmt.Print(root.key, " ")
+		}
 		t.inOrder(root.right)
 	}
 }
 
-func (t *AVLTree) destroyTree(node *AVLNode) {
+func (t *AVLTree[K, V]) destroyTree(node *AVLNode[K, V]) {
 	if node != nil {
 		t.destroyTree(node.left)
 		t.destroyTree(node.right)
@@ -195,101 +405,481 @@ func (t *AVLTree) destroyTree(node *AVLNode) {
 	}
 }
 
-func (t *AVLTree) Insert(key int) {
-	t.root = t.insertNode(t.root, key)
+func (t *AVLTree[K, V]) findNode(key K) *AVLNode[K, V] {
+	curr := t.root
+	for curr != nil {
+		if key == curr.key {
+			return curr
+		}
+		if key < curr.key {
+			curr = curr.left
+		} else {
+			curr = curr.right
+		}
+	}
+	return nil
+}
+
+// Insert adds key with a zero value. An existing key keeps its value.
+func (t *AVLTree[K, V]) Insert(key K) {
+	if t.findNode(key) != nil {
+		return
+	}
+	t.root = t.insertNode(t.root, key, *new(V))
+	t.version++
+}
+
+func (t *AVLTree[K, V]) Remove(key K) {
+	t.Delete(key)
+}
+
+func (t *AVLTree[K, V]) Find(key K) bool {
+	return t.findNode(key) != nil
+}
+
+// Put stores value under key, replacing the value of an existing key.
+func (t *AVLTree[K, V]) Put(key K, value V) {
+	if node := t.findNode(key); node != nil {
+		node.value = value
+		return
+	}
+	t.root = t.insertNode(t.root, key, value)
+	t.version++
 }
 
-func (t *AVLTree) Remove(key int) {
+func (t *AVLTree[K, V]) Get(key K) (V, bool) {
+	if node := t.findNode(key); node != nil {
+		return node.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes key and reports whether it was present.
+func (t *AVLTree[K, V]) Delete(key K) bool {
+	if t.findNode(key) == nil {
+		return false
+	}
 	t.root = t.deleteNode(t.root, key)
+	t.version++
+	return true
+}
+
+func (t *AVLTree[K, V]) Len() int {
+	return t.sizeOf(t.root)
+}
+
+func (t *AVLTree[K, V]) IsEmpty() bool {
+	return t.root == nil
+}
+
+func (t *AVLTree[K, V]) Clear() {
+	t.destroyTree(t.root)
+	t.root = nil
+	t.version++
+}
+
+// Stats returns the rotations done since the tree was created or
+// ResetStats was called. Double rotations count as two.
+func (t *AVLTree[K, V]) Stats() TreeStats {
+	return t.stats
+}
+
+func (t *AVLTree[K, V]) ResetStats() {
+	t.stats = TreeStats{}
+}
+
+// Values returns the keys in ascending order: viewed as a Container the
+// tree is a set of keys. Use Get for the values stored under them.
+func (t *AVLTree[K, V]) Values() []K {
+	keys := make([]K, 0, t.Len())
+	t.collectKeys(t.root, &keys)
+	return keys
+}
+
+// All yields key/value pairs in ascending key order.
+func (t *AVLTree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		version := t.version
+		stack := make([]*AVLNode[K, V], 0, t.height(t.root))
+		curr := t.root
+		for curr != nil || len(stack) > 0 {
+			for curr != nil {
+				stack = append(stack, curr)
+				curr = curr.left
+			}
+			curr = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(curr.key, curr.value) {
+				return
+			}
+			checkVersion(version, t.version)
+			curr = curr.right
+		}
+	}
+}
+
+func (t *AVLTree[K, V]) Min() (K, bool) {
+	if t.root == nil {
+		var zero K
+		return zero, false
+	}
+	return t.minValueNode(t.root).key, true
+}
+
+func (t *AVLTree[K, V]) Max() (K, bool) {
+	if t.root == nil {
+		var zero K
+		return zero, false
+	}
+	curr := t.root
+	for curr.right != nil {
+		curr = curr.right
+	}
+	return curr.key, true
+}
+
+// Floor returns the largest key <= key.
+func (t *AVLTree[K, V]) Floor(key K) (K, bool) {
+	return t.lowerBound(key, true)
+}
+
+// Ceiling returns the smallest key >= key.
+func (t *AVLTree[K, V]) Ceiling(key K) (K, bool) {
+	return t.upperBound(key, true)
+}
+
+// Predecessor returns the largest key < key. key itself need not be stored.
+func (t *AVLTree[K, V]) Predecessor(key K) (K, bool) {
+	return t.lowerBound(key, false)
+}
+
+// Successor returns the smallest key > key. key itself need not be stored.
+func (t *AVLTree[K, V]) Successor(key K) (K, bool) {
+	return t.upperBound(key, false)
 }
 
-func (t *AVLTree) Find(key int) bool {
+func (t *AVLTree[K, V]) lowerBound(key K, inclusive bool) (K, bool) {
+	var best *AVLNode[K, V]
 	curr := t.root
 	for curr != nil {
-		if key == curr.key {
-			return true
+		if curr.key < key || (inclusive && curr.key == key) {
+			best = curr
+			curr = curr.right
+		} else {
+			curr = curr.left
 		}
-		if key < curr.key {
+	}
+	if best == nil {
+		var zero K
+		return zero, false
+	}
+	return best.key, true
+}
+
+func (t *AVLTree[K, V]) upperBound(key K, inclusive bool) (K, bool) {
+	var best *AVLNode[K, V]
+	curr := t.root
+	for curr != nil {
+		if curr.key > key || (inclusive && curr.key == key) {
+			best = curr
 			curr = curr.left
 		} else {
 			curr = curr.right
 		}
 	}
-	return false
+	if best == nil {
+		var zero K
+		return zero, false
+	}
+	return best.key, true
+}
+
+// Range yields the key/value pairs with lo <= key <= hi in ascending order.
+// Subtrees outside the bounds are never visited.
+func (t *AVLTree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		version := t.version
+		stack := make([]*AVLNode[K, V], 0, t.height(t.root))
+		curr := t.root
+		for curr != nil || len(stack) > 0 {
+			for curr != nil {
+				if curr.key < lo {
+					curr = curr.right
+					continue
+				}
+				stack = append(stack, curr)
+				curr = curr.left
+			}
+			if len(stack) == 0 {
+				return
+			}
+			curr = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if curr.key > hi {
+				return
+			}
+			if !yield(curr.key, curr.value) {
+				return
+			}
+			checkVersion(version, t.version)
+			curr = curr.right
+		}
+	}
+}
+
+// CountRange returns the number of keys with lo <= key <= hi in O(log n).
+func (t *AVLTree[K, V]) CountRange(lo, hi K) int {
+	if lo > hi {
+		return 0
+	}
+	return t.countBelow(hi, true) - t.countBelow(lo, false)
+}
+
+// Rank returns the number of keys smaller than key.
+func (t *AVLTree[K, V]) Rank(key K) int {
+	return t.countBelow(key, false)
+}
+
+// Select returns the i-th smallest key, counting from 0.
+func (t *AVLTree[K, V]) Select(i int) (K, bool) {
+	if i < 0 || i >= t.Len() {
+		var zero K
+		return zero, false
+	}
+	curr := t.root
+	for {
+		leftSize := t.sizeOf(curr.left)
+		if i < leftSize {
+			curr = curr.left
+		} else if i > leftSize {
+			i -= leftSize + 1
+			curr = curr.right
+		} else {
+			return curr.key, true
+		}
+	}
+}
+
+// countBelow counts the keys < key, or <= key when inclusive is set.
+func (t *AVLTree[K, V]) countBelow(key K, inclusive bool) int {
+	count := 0
+	curr := t.root
+	for curr != nil {
+		if curr.key < key || (inclusive && curr.key == key) {
+			count += t.sizeOf(curr.left) + 1
+			curr = curr.right
+		} else {
+			curr = curr.left
+		}
+	}
+	return count
 }
 
-func (t *AVLTree) Print() {
+// RemoveRange deletes every key with lo <= key <= hi and returns how many
+// were removed.
+func (t *AVLTree[K, V]) RemoveRange(lo, hi K) int {
+	keys := make([]K, 0)
+	for key := range t.Range(lo, hi) {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		t.Delete(key)
+	}
+	return len(keys)
+}
+
+// Split moves the keys smaller than key into left and the remaining keys
+// into right, in O(log n). t is left empty.
+func (t *AVLTree[K, V]) Split(key K) (left, right *AVLTree[K, V]) {
+	l, found, r := t.splitNode(t.root, key)
+	if found != nil {
+		r = t.joinNodes(nil, found, r)
+	}
+	t.root = nil
+	t.version++
+	return &AVLTree[K, V]{root: l}, &AVLTree[K, V]{root: r}
+}
+
+// Join appends right to t in O(log n). Every key in right must be greater
+// than every key in t. right is left empty.
+func (t *AVLTree[K, V]) Join(right *AVLTree[K, V]) error {
+	if right == t || right.root == nil {
+		return nil
+	}
+	if t.root != nil {
+		maxKey, _ := t.Max()
+		minKey, _ := right.Min()
+		if maxKey >= minKey {
+			return errors.New("keys of the joined tree must be greater than the keys of this tree")
+		}
+	}
+	t.root = t.join2(t.root, right.root)
+	right.root = nil
+	t.version++
+	right.version++
+	return nil
+}
+
+// cloneNodes copies the subtree at n.
+func (t *AVLTree[K, V]) cloneNodes(n *AVLNode[K, V]) *AVLNode[K, V] {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.left = t.cloneNodes(n.left)
+	c.right = t.cloneNodes(n.right)
+	return &c
+}
+
+// Union adds every entry of other to t, keeping t's value where both have
+// the key. other is not modified. With m <= n the sizes of the two trees,
+// it runs in O(m log(n/m + 1)) plus the time to copy the entries it takes
+// from other.
+func (t *AVLTree[K, V]) Union(other *AVLTree[K, V]) {
+	if other == t {
+		return
+	}
+	t.root = t.unionNodes(t.root, other.root)
+	t.version++
+}
+
+// Intersection keeps only the keys of t that other also holds, with t's
+// values, in O(m log(n/m + 1)) like Union. other is not modified.
+func (t *AVLTree[K, V]) Intersection(other *AVLTree[K, V]) {
+	if other == t {
+		return
+	}
+	t.root = t.intersectNodes(t.root, other.root)
+	t.version++
+}
+
+// Difference removes from t every key that other holds, in
+// O(m log(n/m + 1)) like Union. other is not modified.
+func (t *AVLTree[K, V]) Difference(other *AVLTree[K, V]) {
+	if other == t {
+		t.Clear()
+		return
+	}
+	t.root = t.differenceNodes(t.root, other.root)
+	t.version++
+}
+
+// IsSubset reports whether every key of t is also in other by looking each
+// one up in other, in O(m log n) for m keys in t and n in other. Neither
+// tree is modified.
+func (t *AVLTree[K, V]) IsSubset(other *AVLTree[K, V]) bool {
+	if t.Len() > other.Len() {
+		return false
+	}
+	for key := range t.All() {
+		if !other.Find(key) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *AVLTree[K, V]) Print() {
 	fmt.Print("AVLTree (In-order): ")
 	t.inOrder(t.root)
 	fmt.Println()
 }
 
 // Binary Serialization
-func (t *AVLTree) serializeHelper(node *AVLNode, file *os.File) error {
+// Nodes are written in pre-order, each preceded by a presence byte so that
+// any key value (including -1) can be stored.
+func (t *AVLTree[K, V]) serializeHelper(node *AVLNode[K, V], w io.Writer) error {
 	if node == nil {
-		nullMarker := int32(-1)
-		return binary.Write(file, binary.LittleEndian, nullMarker)
+		return binary.Write(w, binary.LittleEndian, uint8(0))
 	}
 
-	if err := binary.Write(file, binary.LittleEndian, int32(node.key)); err != nil {
+	if err := binary.Write(w, binary.LittleEndian, uint8(1)); err != nil {
+		return err
+	}
+	if err := writeValue(w, node.key); err != nil {
+		return err
+	}
+	if err := writeValue(w, node.value); err != nil {
 		return err
 	}
-	if err := t.serializeHelper(node.left, file); err != nil {
+	if err := t.serializeHelper(node.left, w); err != nil {
 		return err
 	}
-	return t.serializeHelper(node.right, file)
+	return t.serializeHelper(node.right, w)
 }
 
-func (t *AVLTree) deserializeHelper(file *os.File) (*AVLNode, error) {
-	var key int32
-	if err := binary.Read(file, binary.LittleEndian, &key); err != nil {
-		if err == io.EOF {
-			return nil, err
-		}
+func (t *AVLTree[K, V]) deserializeHelper(r io.Reader) (*AVLNode[K, V], error) {
+	var marker uint8
+	if err := binary.Read(r, binary.LittleEndian, &marker); err != nil {
 		return nil, err
 	}
 
-	if key == -1 {
+	if marker == 0 {
 		return nil, nil
 	}
 
-	node := &AVLNode{key: int(key), left: nil, right: nil, height: 1}
+	key, err := readValue[K](r)
+	if err != nil {
+		return nil, err
+	}
+	value, err := readValue[V](r)
+	if err != nil {
+		return nil, err
+	}
 
-	left, err := t.deserializeHelper(file)
+	node := &AVLNode[K, V]{key: key, value: value, left: nil, right: nil, height: 1, size: 1}
+	t.version++
+
+	left, err := t.deserializeHelper(r)
 	if err != nil && err != io.EOF {
 		return nil, err
 	}
 	node.left = left
 
-	right, err := t.deserializeHelper(file)
+	right, err := t.deserializeHelper(r)
 	if err != nil && err != io.EOF {
 		return nil, err
 	}
 	node.right = right
 
 	node.height = 1 + t.max(t.height(node.left), t.height(node.right))
+	node.size = 1 + t.sizeOf(node.left) + t.sizeOf(node.right)
 	return node, nil
 }
 
-func (t *AVLTree) Serialize(filename string) error {
+func (t *AVLTree[K, V]) Serialize(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for writing: %w", err)
 	}
 	defer file.Close()
 
-	return t.serializeHelper(t.root, file)
+	return t.SerializeTo(file)
+}
+
+// SerializeTo writes the nodes in pre-order, each as a 1 byte followed by
+// its key and value, with a 0 byte for every missing child. This is not
+// the format of the int-only AVLTree, which wrote int32 keys with -1 for
+// a missing child; such files cannot be read back.
+func (t *AVLTree[K, V]) SerializeTo(w io.Writer) error {
+	return t.serializeHelper(t.root, w)
 }
 
-func (t *AVLTree) Deserialize(filename string) error {
+func (t *AVLTree[K, V]) Deserialize(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for reading: %w", err)
 	}
 	defer file.Close()
 
+	return t.DeserializeFrom(file)
+}
+
+func (t *AVLTree[K, V]) DeserializeFrom(r io.Reader) error {
 	t.destroyTree(t.root)
-	root, err := t.deserializeHelper(file)
+	t.version++
+	root, err := t.deserializeHelper(r)
 	if err != nil && err != io.EOF {
 		return err
 	}
@@ -298,11 +888,12 @@ func (t *AVLTree) Deserialize(filename string) error {
 }
 
 // JSON Serialization
-type avlTreeJSON struct {
-	Keys []int `json:"keys"`
+type avlTreeJSON[K cmp.Ordered, V any] struct {
+	Keys   []K `json:"keys"`
+	Values []V `json:"values,omitempty"` // left out for sets
 }
 
-func (t *AVLTree) collectKeys(node *AVLNode, keys *[]int) {
+func (t *AVLTree[K, V]) collectKeys(node *AVLNode[K, V], keys *[]K) {
 	if node != nil {
 		t.collectKeys(node.left, keys)
 		*keys = append(*keys, node.key)
@@ -310,23 +901,35 @@ func (t *AVLTree) collectKeys(node *AVLNode, keys *[]int) {
 	}
 }
 
-func (t *AVLTree) SerializeJSON(filename string) error {
+func (t *AVLTree[K, V]) collectValues(node *AVLNode[K, V], values *[]V) {
+	if node != nil {
+		t.collectValues(node.left, values)
+		*values = append(*values, node.value)
+		t.collectValues(node.right, values)
+	}
+}
+
+func (t *AVLTree[K, V]) SerializeJSON(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for writing: %w", err)
 	}
 	defer file.Close()
 
-	keys := make([]int, 0)
+	keys := make([]K, 0, t.Len())
 	t.collectKeys(t.root, &keys)
 
-	treeData := avlTreeJSON{Keys: keys}
+	treeData := avlTreeJSON[K, V]{Keys: keys}
+	if t.storesValues() {
+		treeData.Values = make([]V, 0, t.Len())
+		t.collectValues(t.root, &treeData.Values)
+	}
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(treeData)
 }
 
-func (t *AVLTree) DeserializeJSON(filename string) error {
+func (t *AVLTree[K, V]) DeserializeJSON(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("cannot open file for reading: %w", err)
@@ -335,15 +938,23 @@ func (t *AVLTree) DeserializeJSON(filename string) error {
 
 	t.destroyTree(t.root)
 	t.root = nil
+	t.version++
 
-	var treeData avlTreeJSON
+	var treeData avlTreeJSON[K, V]
 	decoder := json.NewDecoder(file)
 	if err := decoder.Decode(&treeData); err != nil {
 		return err
 	}
+	if len(treeData.Values) != 0 && len(treeData.Values) != len(treeData.Keys) {
+		return fmt.Errorf("avl tree json: %d keys but %d values", len(treeData.Keys), len(treeData.Values))
+	}
 
-	for _, key := range treeData.Keys {
-		t.Insert(key)
+	for i, key := range treeData.Keys {
+		if len(treeData.Values) != 0 {
+			t.Put(key, treeData.Values[i])
+		} else {
+			t.Insert(key)
+		}
 	}
 	return nil
 }
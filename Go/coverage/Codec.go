@@ -0,0 +1,96 @@
+package datastructures
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Element encoding shared by the binary serializers.
+//
+// int is written as int32 so files produced by the original int-only
+// structures keep loading. Strings are length-prefixed, fixed-size values
+// (bool, sized numbers, arrays and structs of those) go through
+// encoding/binary, and everything else is stored as length-prefixed JSON.
+
+func isFixedSize(v any) bool {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() == reflect.Slice || t.Kind() == reflect.Pointer {
+		return false
+	}
+	return binary.Size(v) >= 0
+}
+
+func writeValue[T any](w io.Writer, v T) error {
+	switch x := any(v).(type) {
+	case int:
+		return binary.Write(w, binary.LittleEndian, int32(x))
+	case string:
+		return writeBytes(w, []byte(x))
+	}
+	if isFixedSize(v) {
+		return binary.Write(w, binary.LittleEndian, v)
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot encode value: %w", err)
+	}
+	return writeBytes(w, raw)
+}
+
+func readValue[T any](r io.Reader) (T, error) {
+	var v T
+	switch p := any(&v).(type) {
+	case *int:
+		var x int32
+		if err := binary.Read(r, binary.LittleEndian, &x); err != nil {
+			return v, err
+		}
+		*p = int(x)
+		return v, nil
+	case *string:
+		raw, err := readBytes(r)
+		if err != nil {
+			return v, err
+		}
+		*p = string(raw)
+		return v, nil
+	}
+	if isFixedSize(v) {
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	}
+	raw, err := readBytes(r)
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return v, fmt.Errorf("cannot decode value: %w", err)
+	}
+	return v, nil
+}
+
+func writeBytes(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readBytes(r io.Reader) ([]byte, error) {
+	var n uint64
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	if n > 1<<30 {
+		return nil, fmt.Errorf("suspiciously large length %d in file", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,278 @@
+package datastructures
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+type mapShard[K, V any] struct {
+	mu    sync.RWMutex
+	table *HashTableChain[K, V]
+}
+
+// ConcurrentMap is a map that is safe for concurrent use. Keys are spread
+// over a fixed number of HashTableChain shards, each guarded by its own
+// RWMutex, so operations on different shards do not contend.
+type ConcurrentMap[K, V any] struct {
+	shards []*mapShard[K, V]
+	hasher Hasher[K]
+}
+
+func NewConcurrentMap(shardCount int) *ConcurrentMap[int, int] {
+	return NewConcurrentMapOf[int, int](shardCount, IntHasher, nil)
+}
+
+// NewConcurrentMapOf builds a map for any key type, with the same hasher
+// and equality defaults as NewHashTableChainOf. A shardCount <= 0 selects
+// 32 shards.
+func NewConcurrentMapOf[K, V any](shardCount int, hasher Hasher[K], equal func(a, b K) bool) *ConcurrentMap[K, V] {
+	if shardCount <= 0 {
+		shardCount = 32
+	}
+	hasher, equal = resolveHashing(hasher, equal)
+	m := &ConcurrentMap[K, V]{
+		shards: make([]*mapShard[K, V], shardCount),
+		hasher: hasher,
+	}
+	for i := range m.shards {
+		m.shards[i] = &mapShard[K, V]{table: NewHashTableChainOf[K, V](8, hasher, equal)}
+	}
+	return m
+}
+
+// shard picks the shard for key. The hash is mixed first so the shard
+// index does not correlate with the bucket index inside the shard.
+func (m *ConcurrentMap[K, V]) shard(key K) *mapShard[K, V] {
+	return m.shards[mix64(m.hasher(key))%uint64(len(m.shards))]
+}
+
+func (m *ConcurrentMap[K, V]) Load(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table.Get(key)
+}
+
+func (m *ConcurrentMap[K, V]) Store(key K, value V) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.Put(key, value)
+}
+
+// LoadOrStore returns the existing value for key if present. Otherwise it
+// stores value and returns it. loaded reports whether the value was
+// already there.
+func (m *ConcurrentMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.table.Get(key); ok {
+		return old, true
+	}
+	s.table.Put(key, value)
+	return value, false
+}
+
+func (m *ConcurrentMap[K, V]) Delete(key K) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table.Remove(key)
+}
+
+// Compute atomically replaces the entry for key with the result of fn.
+// fn receives the current value and whether it exists; if it returns
+// keep == false the key is deleted. fn runs under the shard lock and must
+// not call back into the map.
+func (m *ConcurrentMap[K, V]) Compute(key K, fn func(old V, loaded bool) (value V, keep bool)) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, loaded := s.table.Get(key)
+	value, keep := fn(old, loaded)
+	if keep {
+		s.table.Put(key, value)
+	} else if loaded {
+		s.table.Remove(key)
+	}
+	return value, keep
+}
+
+// Range calls f for every entry until f returns false. Each shard is
+// copied under its read lock and f runs without any lock held, so f may
+// use the map; entries changed during Range may or may not be seen.
+func (m *ConcurrentMap[K, V]) Range(f func(key K, value V) bool) {
+	for _, s := range m.shards {
+		s.mu.RLock()
+		entries := make([]chainEntry[K, V], 0, s.table.Len())
+		for k, v := range s.table.All() {
+			entries = append(entries, chainEntry[K, V]{Key: k, Value: v})
+		}
+		s.mu.RUnlock()
+
+		for _, e := range entries {
+			if !f(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (m *ConcurrentMap[K, V]) Len() int {
+	n := 0
+	for _, s := range m.shards {
+		s.mu.RLock()
+		n += s.table.Len()
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+func (m *ConcurrentMap[K, V]) Clear() {
+	for _, s := range m.shards {
+		s.mu.Lock()
+		s.table.Clear()
+		s.mu.Unlock()
+	}
+}
+
+// snapshot read-locks every shard, in order, and returns all entries as of
+// one point in time.
+func (m *ConcurrentMap[K, V]) snapshot() []chainEntry[K, V] {
+	for _, s := range m.shards {
+		s.mu.RLock()
+	}
+	defer func() {
+		for _, s := range m.shards {
+			s.mu.RUnlock()
+		}
+	}()
+
+	entries := make([]chainEntry[K, V], 0)
+	for _, s := range m.shards {
+		for k, v := range s.table.All() {
+			entries = append(entries, chainEntry[K, V]{Key: k, Value: v})
+		}
+	}
+	return entries
+}
+
+// replace write-locks every shard and swaps the contents for entries.
+func (m *ConcurrentMap[K, V]) replace(entries []chainEntry[K, V]) {
+	for _, s := range m.shards {
+		s.mu.Lock()
+	}
+	defer func() {
+		for _, s := range m.shards {
+			s.mu.Unlock()
+		}
+	}()
+
+	for _, s := range m.shards {
+		s.table.Clear()
+	}
+	for _, e := range entries {
+		m.shard(e.Key).table.Put(e.Key, e.Value)
+	}
+}
+
+// Binary Serialization
+func (m *ConcurrentMap[K, V]) Serialize(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	return m.SerializeTo(file)
+}
+
+// SerializeTo writes a consistent snapshot: the entry count followed by
+// the entries.
+func (m *ConcurrentMap[K, V]) SerializeTo(w io.Writer) error {
+	entries := m.snapshot()
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(entries))); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := writeValue(w, e.Key); err != nil {
+			return err
+		}
+		if err := writeValue(w, e.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ConcurrentMap[K, V]) Deserialize(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	return m.DeserializeFrom(file)
+}
+
+// DeserializeFrom reads the whole input before touching the map, so other
+// goroutines see either the old or the new contents.
+func (m *ConcurrentMap[K, V]) DeserializeFrom(r io.Reader) error {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return err
+	}
+	if count > 1<<30 {
+		return fmt.Errorf("suspiciously large size %d in file", count)
+	}
+
+	entries := make([]chainEntry[K, V], 0, count)
+	for i := uint64(0); i < count; i++ {
+		key, err := readValue[K](r)
+		if err != nil {
+			return err
+		}
+		value, err := readValue[V](r)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, chainEntry[K, V]{Key: key, Value: value})
+	}
+	m.replace(entries)
+	return nil
+}
+
+// JSON Serialization
+func (m *ConcurrentMap[K, V]) SerializeJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	data := hashTableChainJSON[K, V]{Entries: m.snapshot()}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (m *ConcurrentMap[K, V]) DeserializeJSON(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	var data hashTableChainJSON[K, V]
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+	m.replace(data.Entries)
+	return nil
+}
@@ -0,0 +1,280 @@
+package datastructures
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+)
+
+// Deque is a double-ended queue stored in a growable ring buffer. Pushes
+// and pops at both ends are amortised O(1) and At is O(1); unlike the
+// linked structures it allocates only when the buffer grows or shrinks.
+type Deque[T any] struct {
+	buf     []T // len(buf) is a power of two
+	head    int // slot of the front element
+	size    int
+	version int
+}
+
+// dequeMinCapacity is the smallest buffer a Deque shrinks to.
+const dequeMinCapacity = 8
+
+func NewDeque() *Deque[int] {
+	return NewDequeOf[int]()
+}
+
+func NewDequeOf[T any]() *Deque[T] {
+	return &Deque[T]{buf: make([]T, dequeMinCapacity)}
+}
+
+// slot maps a position from the front to a buffer index.
+func (d *Deque[T]) slot(i int) int {
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+// resize copies the elements, front first, into a buffer of newCap slots.
+func (d *Deque[T]) resize(newCap int) {
+	newBuf := make([]T, newCap)
+	if d.head+d.size <= len(d.buf) {
+		copy(newBuf, d.buf[d.head:d.head+d.size])
+	} else {
+		n := copy(newBuf, d.buf[d.head:])
+		copy(newBuf[n:], d.buf[:d.size-n])
+	}
+	d.buf = newBuf
+	d.head = 0
+}
+
+func (d *Deque[T]) grow() {
+	if d.size == len(d.buf) {
+		d.resize(2 * len(d.buf))
+	}
+}
+
+// shrink halves the buffer once it is a quarter full.
+func (d *Deque[T]) shrink() {
+	if len(d.buf) > dequeMinCapacity && d.size <= len(d.buf)/4 {
+		d.resize(len(d.buf) / 2)
+	}
+}
+
+func (d *Deque[T]) PushBack(value T) {
+	d.grow()
+	d.buf[d.slot(d.size)] = value
+	d.size++
+	d.version++
+}
+
+func (d *Deque[T]) PushFront(value T) {
+	d.grow()
+	d.head = d.slot(len(d.buf) - 1)
+	d.buf[d.head] = value
+	d.size++
+	d.version++
+}
+
+func (d *Deque[T]) PopFront() (T, error) {
+	var zero T
+	if d.size == 0 {
+		return zero, errors.New("deque empty")
+	}
+	value := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = d.slot(1)
+	d.size--
+	d.version++
+	d.shrink()
+	return value, nil
+}
+
+func (d *Deque[T]) PopBack() (T, error) {
+	var zero T
+	if d.size == 0 {
+		return zero, errors.New("deque empty")
+	}
+	idx := d.slot(d.size - 1)
+	value := d.buf[idx]
+	d.buf[idx] = zero
+	d.size--
+	d.version++
+	d.shrink()
+	return value, nil
+}
+
+func (d *Deque[T]) Front() (T, error) {
+	if d.size == 0 {
+		var zero T
+		return zero, errors.New("deque empty")
+	}
+	return d.buf[d.head], nil
+}
+
+func (d *Deque[T]) Back() (T, error) {
+	if d.size == 0 {
+		var zero T
+		return zero, errors.New("deque empty")
+	}
+	return d.buf[d.slot(d.size-1)], nil
+}
+
+// At returns the element index positions behind the front.
+func (d *Deque[T]) At(index int) (T, error) {
+	if index < 0 || index >= d.size {
+		var zero T
+		return zero, errors.New("index out of bounds")
+	}
+	return d.buf[d.slot(index)], nil
+}
+
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+func (d *Deque[T]) Clear() {
+	d.buf = make([]T, dequeMinCapacity)
+	d.head = 0
+	d.size = 0
+	d.version++
+}
+
+// Values returns the elements from front to back.
+func (d *Deque[T]) Values() []T {
+	values := make([]T, 0, d.size)
+	for i := 0; i < d.size; i++ {
+		values = append(values, d.buf[d.slot(i)])
+	}
+	return values
+}
+
+// All yields the elements from front to back.
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		version := d.version
+		for i := 0; i < d.size; i++ {
+			if !yield(d.buf[d.slot(i)]) {
+				return
+			}
+			checkVersion(version, d.version)
+		}
+	}
+}
+
+// Backward yields the elements from back to front.
+func (d *Deque[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		version := d.version
+		for i := d.size - 1; i >= 0; i-- {
+			if !yield(d.buf[d.slot(i)]) {
+				return
+			}
+			checkVersion(version, d.version)
+		}
+	}
+}
+
+func (d *Deque[T]) Print() {
+	fmt.Print("Deque [")
+	for i := 0; i < d.size; i++ {
+		fmt.Print(d.buf[d.slot(i)])
+		if i < d.size-1 {
+			fmt.Print(", ")
+		}
+	}
+	fmt.Println("]")
+}
+
+// Binary Serialization
+func (d *Deque[T]) Serialize(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	return d.SerializeTo(file)
+}
+
+func (d *Deque[T]) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(d.size)); err != nil {
+		return err
+	}
+	for i := 0; i < d.size; i++ {
+		if err := writeValue(w, d.buf[d.slot(i)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Deque[T]) Deserialize(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	return d.DeserializeFrom(file)
+}
+
+func (d *Deque[T]) DeserializeFrom(r io.Reader) error {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return err
+	}
+
+	d.Clear()
+	for i := uint64(0); i < count; i++ {
+		value, err := readValue[T](r)
+		if err != nil {
+			return err
+		}
+		d.PushBack(value)
+	}
+	return nil
+}
+
+// JSON Serialization
+type dequeJSON[T any] struct {
+	Data []T `json:"data"`
+}
+
+func (d *Deque[T]) SerializeJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	data := dequeJSON[T]{Data: d.Values()}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (d *Deque[T]) DeserializeJSON(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	var data dequeJSON[T]
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+
+	d.Clear()
+	for _, value := range data.Data {
+		d.PushBack(value)
+	}
+	return nil
+}
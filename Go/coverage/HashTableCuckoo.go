@@ -0,0 +1,409 @@
+package datastructures
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand/v2"
+	"os"
+)
+
+type cuckooSlot[K, V any] struct {
+	key        K
+	value      V
+	isOccupied bool
+}
+
+// HashTableCuckoo keeps every key in one of two candidate slots, one per
+// table, so Get and Remove inspect at most two slots plus a small stash.
+// Insert evicts the occupant of a full slot into its other candidate, for
+// at most cuckooMaxKicks moves; a longer chain is treated as a cycle and
+// the entry left over goes to the stash. Once the stash holds
+// cuckooStashSize entries the table is rebuilt with fresh hash seeds, and
+// with twice the capacity if the seeds do not help, so a lookup costs at
+// most two slots and cuckooStashSize stash entries. The one exception is
+// a hasher that maps more than two keys to the same value: no seed or
+// capacity separates those, and the ones left over stay in the stash.
+type HashTableCuckoo[K, V any] struct {
+	tables   [2][]cuckooSlot[K, V]
+	stash    []cuckooSlot[K, V] // entries without a slot, scanned last
+	seeds    [2]uint64
+	size     int
+	capacity int // slots per table
+	hasher   Hasher[K]
+	equal    func(a, b K) bool
+	version  int
+}
+
+const (
+	cuckooMaxKicks = 64
+	// cuckooMaxLoad is the share of all slots that may be used. Two-table
+	// cuckoo hashing stops finding free slots quickly above one half.
+	cuckooMaxLoad = 0.5
+	// cuckooStashSize is how many entries may wait in the stash before an
+	// insert that needs it rebuilds the table.
+	cuckooStashSize = 4
+	// cuckooMaxReseeds is how many seed pairs a rebuild tries at each
+	// capacity.
+	cuckooMaxReseeds = 3
+)
+
+func NewHashTableCuckoo(initCap int) *HashTableCuckoo[int, int] {
+	return NewHashTableCuckooOf[int, int](initCap, IntBitsHasher, nil)
+}
+
+// NewHashTableCuckooOf builds a table for any key type, with the same
+// hasher and equality defaults as NewHashTableChainOf. initCap is the
+// number of slots in each of the two tables.
+func NewHashTableCuckooOf[K, V any](initCap int, hasher Hasher[K], equal func(a, b K) bool) *HashTableCuckoo[K, V] {
+	if initCap <= 0 {
+		initCap = 8
+	}
+	hasher, equal = resolveHashing(hasher, equal)
+	h := &HashTableCuckoo[K, V]{hasher: hasher, equal: equal}
+	h.reset(initCap)
+	return h
+}
+
+func (h *HashTableCuckoo[K, V]) reset(capacity int) {
+	h.capacity = capacity
+	h.tables[0] = make([]cuckooSlot[K, V], capacity)
+	h.tables[1] = make([]cuckooSlot[K, V], capacity)
+	h.stash = nil
+	h.seeds = [2]uint64{rand.Uint64(), rand.Uint64()}
+	h.size = 0
+	h.version++
+}
+
+// Capacity returns the total number of slots in both tables.
+func (h *HashTableCuckoo[K, V]) Capacity() int {
+	return 2 * h.capacity
+}
+
+func (h *HashTableCuckoo[K, V]) index(t int, key K) int {
+	return int(mix64(h.hasher(key)^h.seeds[t]) % uint64(h.capacity))
+}
+
+// find returns the table and slot holding key, with table 2 standing for
+// the stash, or -1, -1.
+func (h *HashTableCuckoo[K, V]) find(key K) (int, int) {
+	for t := 0; t < 2; t++ {
+		idx := h.index(t, key)
+		if h.tables[t][idx].isOccupied && h.equal(h.tables[t][idx].key, key) {
+			return t, idx
+		}
+	}
+	for i := range h.stash {
+		if h.equal(h.stash[i].key, key) {
+			return 2, i
+		}
+	}
+	return -1, -1
+}
+
+// at returns the slot that find reported.
+func (h *HashTableCuckoo[K, V]) at(t, idx int) *cuckooSlot[K, V] {
+	if t == 2 {
+		return &h.stash[idx]
+	}
+	return &h.tables[t][idx]
+}
+
+// place runs the eviction chain for e. If it gives up, the entry left
+// without a slot is returned.
+func (h *HashTableCuckoo[K, V]) place(e cuckooSlot[K, V]) (cuckooSlot[K, V], bool) {
+	t := 0
+	for kick := 0; kick < cuckooMaxKicks; kick++ {
+		idx := h.index(t, e.key)
+		if !h.tables[t][idx].isOccupied {
+			h.tables[t][idx] = e
+			return e, true
+		}
+		e, h.tables[t][idx] = h.tables[t][idx], e
+		t = 1 - t
+	}
+	return e, false
+}
+
+// rehash rebuilds the tables with all entries and extra. It tries up to
+// cuckooMaxReseeds seed pairs at newCap and stops at the first that
+// leaves no more than cuckooStashSize entries in the stash; if none does,
+// it doubles the capacity and tries again. When doubling leaves as many
+// entries over as before, they share their hashes and more room will not
+// help, so the table goes back to the smaller capacity.
+func (h *HashTableCuckoo[K, V]) rehash(newCap int, extra ...cuckooSlot[K, V]) {
+	entries := append(extra, h.stash...)
+	for t := 0; t < 2; t++ {
+		for _, slot := range h.tables[t] {
+			if slot.isOccupied {
+				entries = append(entries, slot)
+			}
+		}
+	}
+
+	left := -1 // stash length at the previous capacity
+	for {
+		for attempt := 0; attempt < cuckooMaxReseeds; attempt++ {
+			h.fill(newCap, entries)
+			if len(h.stash) <= cuckooStashSize {
+				return
+			}
+		}
+		if left >= 0 && len(h.stash) >= left {
+			h.fill(newCap/2, entries)
+			return
+		}
+		left = len(h.stash)
+		newCap *= 2
+	}
+}
+
+// fill resets the tables to capacity with new seeds and places entries,
+// stashing the ones the eviction chain gives up on.
+func (h *HashTableCuckoo[K, V]) fill(capacity int, entries []cuckooSlot[K, V]) {
+	h.reset(capacity)
+	for _, e := range entries {
+		if homeless, ok := h.place(e); !ok {
+			h.stash = append(h.stash, homeless)
+		}
+	}
+	h.size = len(entries)
+}
+
+// Put stores value under key, replacing the value if key is present.
+func (h *HashTableCuckoo[K, V]) Put(key K, value V) {
+	if t, idx := h.find(key); t >= 0 {
+		h.at(t, idx).value = value
+		return
+	}
+
+	if float64(h.size+1) > float64(2*h.capacity)*cuckooMaxLoad {
+		h.rehash(2 * h.capacity)
+	}
+	e := cuckooSlot[K, V]{key: key, value: value, isOccupied: true}
+	if homeless, ok := h.place(e); !ok {
+		if len(h.stash) >= cuckooStashSize {
+			h.rehash(h.capacity, homeless)
+			return
+		}
+		h.stash = append(h.stash, homeless)
+	}
+	h.size++
+	h.version++
+}
+
+// Insert is Put under the name the Map interface uses.
+func (h *HashTableCuckoo[K, V]) Insert(key K, value V) {
+	h.Put(key, value)
+}
+
+func (h *HashTableCuckoo[K, V]) Get(key K) (V, bool) {
+	if t, idx := h.find(key); t >= 0 {
+		return h.at(t, idx).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (h *HashTableCuckoo[K, V]) Remove(key K) {
+	t, idx := h.find(key)
+	if t < 0 {
+		return
+	}
+	if t == 2 {
+		last := len(h.stash) - 1
+		h.stash[idx] = h.stash[last]
+		h.stash = h.stash[:last]
+	} else {
+		h.tables[t][idx] = cuckooSlot[K, V]{}
+	}
+	h.size--
+	h.version++
+}
+
+func (h *HashTableCuckoo[K, V]) Len() int {
+	return h.size
+}
+
+func (h *HashTableCuckoo[K, V]) IsEmpty() bool {
+	return h.size == 0
+}
+
+func (h *HashTableCuckoo[K, V]) Keys() []K {
+	keys := make([]K, 0, h.size)
+	for k := range h.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (h *HashTableCuckoo[K, V]) Values() []V {
+	values := make([]V, 0, h.size)
+	for _, v := range h.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+func (h *HashTableCuckoo[K, V]) Clear() {
+	h.reset(h.capacity)
+}
+
+// All yields key/value pairs of the first table, then the second, then
+// the stash.
+func (h *HashTableCuckoo[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		version := h.version
+		for t := 0; t < 2; t++ {
+			for i := 0; i < h.capacity; i++ {
+				slot := h.tables[t][i]
+				if !slot.isOccupied {
+					continue
+				}
+				if !yield(slot.key, slot.value) {
+					return
+				}
+				checkVersion(version, h.version)
+			}
+		}
+		for i := 0; i < len(h.stash); i++ {
+			if !yield(h.stash[i].key, h.stash[i].value) {
+				return
+			}
+			checkVersion(version, h.version)
+		}
+	}
+}
+
+func (h *HashTableCuckoo[K, V]) Print() {
+	fmt.Println("HashTableCuckoo:")
+	for t := 0; t < 2; t++ {
+		for i := 0; i < h.capacity; i++ {
+			if h.tables[t][i].isOccupied {
+				fmt.Printf("[%d:%d]: %v -> %v\n", t, i, h.tables[t][i].key, h.tables[t][i].value)
+			}
+		}
+	}
+	for _, slot := range h.stash {
+		fmt.Printf("[stash]: %v -> %v\n", slot.key, slot.value)
+	}
+}
+
+// Binary Serialization
+func (h *HashTableCuckoo[K, V]) Serialize(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	return h.SerializeTo(file)
+}
+
+// SerializeTo writes the size, the per-table capacity and the entries.
+// Slot positions depend on the random seeds, so they are not stored.
+func (h *HashTableCuckoo[K, V]) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(h.size)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint64(h.capacity)); err != nil {
+		return err
+	}
+
+	for k, v := range h.All() {
+		if err := writeValue(w, k); err != nil {
+			return err
+		}
+		if err := writeValue(w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *HashTableCuckoo[K, V]) Deserialize(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	return h.DeserializeFrom(file)
+}
+
+func (h *HashTableCuckoo[K, V]) DeserializeFrom(r io.Reader) error {
+	var size, capacity uint64
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &capacity); err != nil {
+		return err
+	}
+	if capacity == 0 || capacity > 1<<30 || size > 2*capacity {
+		return fmt.Errorf("invalid size %d or capacity %d in file", size, capacity)
+	}
+
+	h.reset(int(capacity))
+	for i := uint64(0); i < size; i++ {
+		key, err := readValue[K](r)
+		if err != nil {
+			return err
+		}
+		value, err := readValue[V](r)
+		if err != nil {
+			return err
+		}
+		h.Insert(key, value)
+	}
+	return nil
+}
+
+// JSON Serialization
+type cuckooEntry[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type hashTableCuckooJSON[K, V any] struct {
+	Entries []cuckooEntry[K, V] `json:"entries"`
+}
+
+func (h *HashTableCuckoo[K, V]) SerializeJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	entries := make([]cuckooEntry[K, V], 0, h.size)
+	for k, v := range h.All() {
+		entries = append(entries, cuckooEntry[K, V]{Key: k, Value: v})
+	}
+
+	data := hashTableCuckooJSON[K, V]{Entries: entries}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (h *HashTableCuckoo[K, V]) DeserializeJSON(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	var data hashTableCuckooJSON[K, V]
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+
+	h.reset(h.capacity)
+	for _, entry := range data.Entries {
+		h.Insert(entry.Key, entry.Value)
+	}
+	return nil
+}
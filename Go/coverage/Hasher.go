@@ -0,0 +1,99 @@
+package datastructures
+
+import (
+	"bytes"
+	"hash/maphash"
+)
+
+// Hasher maps a key to a 64-bit hash. Tables reduce it to a bucket index
+// themselves, so a Hasher does not need to know the table capacity.
+type Hasher[K any] func(key K) uint64
+
+// IntHasher hashes an int by its absolute value, which is what the int-only
+// tables always did. It stays in integer arithmetic, so large keys no
+// longer collide through float rounding.
+func IntHasher(key int) uint64 {
+	if key < 0 {
+		return uint64(-key)
+	}
+	return uint64(key)
+}
+
+// IntBitsHasher hashes an int by its bits, so unlike IntHasher it keeps k
+// and -k apart. NewHashTableCuckoo uses it: keys with the same hash can
+// only share two slots there, and any more wait in its stash.
+func IntBitsHasher(key int) uint64 {
+	return uint64(key)
+}
+
+// StringHasher returns a maphash-based hasher with a fresh random seed.
+func StringHasher() Hasher[string] {
+	seed := maphash.MakeSeed()
+	return func(key string) uint64 {
+		return maphash.String(seed, key)
+	}
+}
+
+// BytesHasher returns a maphash-based hasher for byte slices with a fresh
+// random seed.
+func BytesHasher() Hasher[[]byte] {
+	seed := maphash.MakeSeed()
+	return func(key []byte) uint64 {
+		return maphash.Bytes(seed, key)
+	}
+}
+
+// mix64 is the splitmix64 finalizer. It spreads hashes whose entropy sits
+// in the low bits, such as IntHasher's, over the whole word.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// defaultHasher returns the hasher used when a table is built without one,
+// or nil if K has no default.
+func defaultHasher[K any]() Hasher[K] {
+	switch any(*new(K)).(type) {
+	case int:
+		return any(Hasher[int](IntHasher)).(Hasher[K])
+	case string:
+		return any(StringHasher()).(Hasher[K])
+	case []byte:
+		return any(BytesHasher()).(Hasher[K])
+	}
+	return nil
+}
+
+// defaultEqual returns == for K, except for byte slices which are compared
+// by content. Keys whose dynamic type is not comparable need an explicit
+// equality function.
+func defaultEqual[K any]() func(a, b K) bool {
+	switch any(*new(K)).(type) {
+	case int:
+		return any(func(a, b int) bool { return a == b }).(func(a, b K) bool)
+	case string:
+		return any(func(a, b string) bool { return a == b }).(func(a, b K) bool)
+	case []byte:
+		return any(bytes.Equal).(func(a, b K) bool)
+	}
+	return func(a, b K) bool {
+		return any(a) == any(b)
+	}
+}
+
+func resolveHashing[K any](hasher Hasher[K], equal func(a, b K) bool) (Hasher[K], func(a, b K) bool) {
+	if hasher == nil {
+		hasher = defaultHasher[K]()
+	}
+	if hasher == nil {
+		panic("datastructures: no default hasher for this key type, pass one explicitly")
+	}
+	if equal == nil {
+		equal = defaultEqual[K]()
+	}
+	return hasher, equal
+}
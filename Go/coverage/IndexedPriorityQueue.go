@@ -0,0 +1,339 @@
+package datastructures
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+)
+
+// PQItem is the handle Push returns for an IndexedPriorityQueue entry. It
+// stays valid until the entry is popped or removed.
+type PQItem[V, P any] struct {
+	value    V
+	priority P
+	index    int // position in the heap, -1 once the item left the queue
+}
+
+func (it *PQItem[V, P]) Value() V {
+	return it.value
+}
+
+func (it *PQItem[V, P]) Priority() P {
+	return it.priority
+}
+
+// IndexedPriorityQueue is a binary heap of values ordered by a separate
+// priority. Every item knows its heap position, so Update and Remove find
+// it in O(1) and fix the heap in O(log n): the decrease-key operation
+// Dijkstra's and Prim's algorithms need.
+type IndexedPriorityQueue[V, P any] struct {
+	items   []*PQItem[V, P]
+	less    func(a, b P) bool
+	version int
+}
+
+// NewIndexedPriorityQueue returns a queue of ints with int priorities that
+// pops the lowest priority first.
+func NewIndexedPriorityQueue() *IndexedPriorityQueue[int, int] {
+	return NewIndexedPriorityQueueOf[int](func(a, b int) bool { return a < b })
+}
+
+func NewIndexedPriorityQueueOf[V, P any](less func(a, b P) bool) *IndexedPriorityQueue[V, P] {
+	if less == nil {
+		panic("datastructures: IndexedPriorityQueue needs a less function")
+	}
+	return &IndexedPriorityQueue[V, P]{less: less}
+}
+
+func (pq *IndexedPriorityQueue[V, P]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *IndexedPriorityQueue[V, P]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.items[i].priority, pq.items[parent].priority) {
+			return
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down sifts the item at i towards the leaves and reports whether it
+// moved.
+func (pq *IndexedPriorityQueue[V, P]) down(i int) bool {
+	start := i
+	n := len(pq.items)
+	for {
+		first := i
+		if l := 2*i + 1; l < n && pq.less(pq.items[l].priority, pq.items[first].priority) {
+			first = l
+		}
+		if r := 2*i + 2; r < n && pq.less(pq.items[r].priority, pq.items[first].priority) {
+			first = r
+		}
+		if first == i {
+			return i != start
+		}
+		pq.swap(i, first)
+		i = first
+	}
+}
+
+// fix moves the item at i up or down after its priority changed.
+func (pq *IndexedPriorityQueue[V, P]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+// contains reports whether item is an entry of this queue.
+func (pq *IndexedPriorityQueue[V, P]) contains(item *PQItem[V, P]) bool {
+	return item != nil && item.index >= 0 && item.index < len(pq.items) && pq.items[item.index] == item
+}
+
+// Push adds value with the given priority and returns its handle.
+func (pq *IndexedPriorityQueue[V, P]) Push(value V, priority P) *PQItem[V, P] {
+	item := &PQItem[V, P]{value: value, priority: priority, index: len(pq.items)}
+	pq.items = append(pq.items, item)
+	pq.up(item.index)
+	pq.version++
+	return item
+}
+
+// Pop removes and returns the value with the first priority.
+func (pq *IndexedPriorityQueue[V, P]) Pop() (V, P, error) {
+	if len(pq.items) == 0 {
+		var zeroV V
+		var zeroP P
+		return zeroV, zeroP, errors.New("priority queue empty")
+	}
+	item := pq.items[0]
+	pq.removeAt(0)
+	return item.value, item.priority, nil
+}
+
+func (pq *IndexedPriorityQueue[V, P]) Peek() (V, P, error) {
+	if len(pq.items) == 0 {
+		var zeroV V
+		var zeroP P
+		return zeroV, zeroP, errors.New("priority queue empty")
+	}
+	return pq.items[0].value, pq.items[0].priority, nil
+}
+
+// Update changes the priority of item, in either direction.
+func (pq *IndexedPriorityQueue[V, P]) Update(item *PQItem[V, P], priority P) error {
+	if !pq.contains(item) {
+		return errors.New("item not in queue")
+	}
+	item.priority = priority
+	pq.fix(item.index)
+	pq.version++
+	return nil
+}
+
+// Remove takes item out of the queue wherever it is in the heap.
+func (pq *IndexedPriorityQueue[V, P]) Remove(item *PQItem[V, P]) error {
+	if !pq.contains(item) {
+		return errors.New("item not in queue")
+	}
+	pq.removeAt(item.index)
+	return nil
+}
+
+func (pq *IndexedPriorityQueue[V, P]) removeAt(i int) {
+	item := pq.items[i]
+	last := len(pq.items) - 1
+	if i != last {
+		pq.swap(i, last)
+	}
+	pq.items[last] = nil
+	pq.items = pq.items[:last]
+	if i != last {
+		pq.fix(i)
+	}
+	item.index = -1
+	pq.version++
+}
+
+// Contains reports whether item is still in the queue.
+func (pq *IndexedPriorityQueue[V, P]) Contains(item *PQItem[V, P]) bool {
+	return pq.contains(item)
+}
+
+func (pq *IndexedPriorityQueue[V, P]) Len() int {
+	return len(pq.items)
+}
+
+func (pq *IndexedPriorityQueue[V, P]) IsEmpty() bool {
+	return len(pq.items) == 0
+}
+
+// Clear empties the queue and invalidates every handle.
+func (pq *IndexedPriorityQueue[V, P]) Clear() {
+	for _, item := range pq.items {
+		item.index = -1
+	}
+	pq.items = nil
+	pq.version++
+}
+
+// Values returns the values in heap order.
+func (pq *IndexedPriorityQueue[V, P]) Values() []V {
+	values := make([]V, 0, len(pq.items))
+	for _, item := range pq.items {
+		values = append(values, item.value)
+	}
+	return values
+}
+
+// All yields value/priority pairs in heap order.
+func (pq *IndexedPriorityQueue[V, P]) All() iter.Seq2[V, P] {
+	return func(yield func(V, P) bool) {
+		version := pq.version
+		for _, item := range pq.items {
+			if !yield(item.value, item.priority) {
+				return
+			}
+			checkVersion(version, pq.version)
+		}
+	}
+}
+
+func (pq *IndexedPriorityQueue[V, P]) Print() {
+	fmt.Print("IndexedPriorityQueue [")
+	for i, item := range pq.items {
+		if i > 0 {
+			fmt.Print(", ")
+		}
+		fmt.Printf("%v:%v", item.value, item.priority)
+	}
+	fmt.Println("]")
+}
+
+// Binary Serialization
+func (pq *IndexedPriorityQueue[V, P]) Serialize(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	return pq.SerializeTo(file)
+}
+
+// SerializeTo writes the count followed by value/priority pairs in heap
+// order. Handles are not part of the file.
+func (pq *IndexedPriorityQueue[V, P]) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(pq.items))); err != nil {
+		return err
+	}
+	for _, item := range pq.items {
+		if err := writeValue(w, item.value); err != nil {
+			return err
+		}
+		if err := writeValue(w, item.priority); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pq *IndexedPriorityQueue[V, P]) Deserialize(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	return pq.DeserializeFrom(file)
+}
+
+// DeserializeFrom replaces the contents and invalidates existing handles.
+// The loaded entries have no handles, so they can only be popped.
+func (pq *IndexedPriorityQueue[V, P]) DeserializeFrom(r io.Reader) error {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return err
+	}
+
+	entries := make([]pqEntry[V, P], 0, min(count, 1<<16))
+	for i := uint64(0); i < count; i++ {
+		value, err := readValue[V](r)
+		if err != nil {
+			return err
+		}
+		priority, err := readValue[P](r)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, pqEntry[V, P]{Value: value, Priority: priority})
+	}
+	pq.load(entries)
+	return nil
+}
+
+// load replaces the contents with entries and heapifies them in O(n).
+func (pq *IndexedPriorityQueue[V, P]) load(entries []pqEntry[V, P]) {
+	pq.Clear()
+	pq.items = make([]*PQItem[V, P], len(entries))
+	for i, e := range entries {
+		pq.items[i] = &PQItem[V, P]{value: e.Value, priority: e.Priority, index: i}
+	}
+	for i := len(pq.items)/2 - 1; i >= 0; i-- {
+		pq.down(i)
+	}
+}
+
+// JSON Serialization
+type pqEntry[V, P any] struct {
+	Value    V `json:"value"`
+	Priority P `json:"priority"`
+}
+
+type indexedPriorityQueueJSON[V, P any] struct {
+	Items []pqEntry[V, P] `json:"items"`
+}
+
+func (pq *IndexedPriorityQueue[V, P]) SerializeJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	entries := make([]pqEntry[V, P], 0, len(pq.items))
+	for _, item := range pq.items {
+		entries = append(entries, pqEntry[V, P]{Value: item.value, Priority: item.priority})
+	}
+
+	data := indexedPriorityQueueJSON[V, P]{Items: entries}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (pq *IndexedPriorityQueue[V, P]) DeserializeJSON(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	var data indexedPriorityQueueJSON[V, P]
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+
+	pq.load(data.Items)
+	return nil
+}
@@ -0,0 +1,16 @@
+package datastructures
+
+import "errors"
+
+// ErrModifiedDuringIteration is the panic value raised by an All or
+// Backward iterator when elements are added to or removed from its
+// container while the loop is running. Each structure keeps a version
+// counter that is bumped on such changes; updating a value in place does
+// not count.
+var ErrModifiedDuringIteration = errors.New("container modified during iteration")
+
+func checkVersion(expected, actual int) {
+	if expected != actual {
+		panic(ErrModifiedDuringIteration)
+	}
+}
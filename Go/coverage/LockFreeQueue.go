@@ -0,0 +1,112 @@
+package datastructures
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+type lockFreeNode struct {
+	data int
+	next atomic.Pointer[lockFreeNode]
+}
+
+// LockFreeQueue is the Michael-Scott queue: an unbounded FIFO of ints that
+// any number of goroutines can use at once without locks. head always
+// points at a dummy node whose successor is the front element; tail points
+// at the last node or, while a Push is half done, at the one before it,
+// and any operation that notices this swings it forward first.
+//
+// Nodes are never reused, so the garbage collector rules out the ABA
+// problem that the original algorithm needs tagged pointers for.
+type LockFreeQueue struct {
+	head atomic.Pointer[lockFreeNode]
+	tail atomic.Pointer[lockFreeNode]
+	size atomic.Int64
+}
+
+func NewLockFreeQueue() *LockFreeQueue {
+	q := &LockFreeQueue{}
+	dummy := &lockFreeNode{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+	return q
+}
+
+func (q *LockFreeQueue) Push(value int) {
+	node := &lockFreeNode{data: value}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// Another Push linked its node but has not moved tail yet
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, node) {
+			q.tail.CompareAndSwap(tail, node)
+			q.size.Add(1)
+			return
+		}
+	}
+}
+
+// TryPop removes and returns the front element, or reports false if the
+// queue is empty. Unlike Peek followed by Pop it is a single atomic step,
+// so concurrent consumers should use it.
+func (q *LockFreeQueue) TryPop() (int, bool) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if next == nil {
+			return 0, false
+		}
+		if head == tail {
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if q.head.CompareAndSwap(head, next) {
+			q.size.Add(-1)
+			return next.data, true
+		}
+	}
+}
+
+// Pop removes the front element, if any, like MyQueue.Pop.
+func (q *LockFreeQueue) Pop() {
+	q.TryPop()
+}
+
+func (q *LockFreeQueue) Peek() (int, error) {
+	next := q.head.Load().next.Load()
+	if next == nil {
+		return 0, errors.New("queue empty")
+	}
+	return next.data, nil
+}
+
+// Len returns the number of elements. Under concurrent use it is only a
+// snapshot, and a Pop can be counted before the matching Push.
+func (q *LockFreeQueue) Len() int {
+	return max(int(q.size.Load()), 0)
+}
+
+func (q *LockFreeQueue) IsEmpty() bool {
+	return q.head.Load().next.Load() == nil
+}
+
+// Values returns the elements from front to back. Elements pushed or
+// popped while it runs may or may not be included.
+func (q *LockFreeQueue) Values() []int {
+	values := make([]int, 0, q.Len())
+	for curr := q.head.Load().next.Load(); curr != nil; curr = curr.next.Load() {
+		values = append(values, curr.data)
+	}
+	return values
+}
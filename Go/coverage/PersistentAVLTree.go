@@ -0,0 +1,282 @@
+package datastructures
+
+import (
+	"cmp"
+	"io"
+	"iter"
+)
+
+// PersistentAVLTree is an immutable AVLTree. Insert, Put and Remove copy
+// only the O(log n) nodes on the path to the key and return a new version;
+// every other node is shared, so older versions stay valid and can be read
+// concurrently with writers producing new ones.
+type PersistentAVLTree[K cmp.Ordered, V any] struct {
+	root *AVLNode[K, V]
+}
+
+func NewPersistentAVLTree() *PersistentAVLTree[int, struct{}] {
+	return NewPersistentAVLTreeOf[int, struct{}]()
+}
+
+func NewPersistentAVLTreeOf[K cmp.Ordered, V any]() *PersistentAVLTree[K, V] {
+	return &PersistentAVLTree[K, V]{root: nil}
+}
+
+// Snapshot returns the current version in O(1). Since versions never
+// change, it is the tree itself; it exists so readers can pin a version
+// while the writer keeps replacing its own reference.
+func (t *PersistentAVLTree[K, V]) Snapshot() *PersistentAVLTree[K, V] {
+	return t
+}
+
+// view wraps the shared nodes in an AVLTree so the read-only queries can be
+// reused. The result must never be modified.
+func (t *PersistentAVLTree[K, V]) view() *AVLTree[K, V] {
+	return &AVLTree[K, V]{root: t.root}
+}
+
+func (t *PersistentAVLTree[K, V]) copyNode(n *AVLNode[K, V]) *AVLNode[K, V] {
+	c := *n
+	return &c
+}
+
+func (t *PersistentAVLTree[K, V]) fix(n *AVLNode[K, V]) {
+	lh, rh := 0, 0
+	ls, rs := 0, 0
+	if n.left != nil {
+		lh, ls = n.left.height, n.left.size
+	}
+	if n.right != nil {
+		rh, rs = n.right.height, n.right.size
+	}
+	n.height = 1 + max(lh, rh)
+	n.size = 1 + ls + rs
+}
+
+func (t *PersistentAVLTree[K, V]) heightOf(n *AVLNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// The rotations receive a node that is already a private copy and copy the
+// child they promote before relinking it.
+
+func (t *PersistentAVLTree[K, V]) rotateRight(y *AVLNode[K, V]) *AVLNode[K, V] {
+	x := t.copyNode(y.left)
+	y.left = x.right
+	x.right = y
+	t.fix(y)
+	t.fix(x)
+	return x
+}
+
+func (t *PersistentAVLTree[K, V]) rotateLeft(x *AVLNode[K, V]) *AVLNode[K, V] {
+	y := t.copyNode(x.right)
+	x.right = y.left
+	y.left = x
+	t.fix(x)
+	t.fix(y)
+	return y
+}
+
+// rebalance restores the AVL invariant at n, which must be a private copy.
+func (t *PersistentAVLTree[K, V]) rebalance(n *AVLNode[K, V]) *AVLNode[K, V] {
+	t.fix(n)
+	balance := t.heightOf(n.left) - t.heightOf(n.right)
+
+	if balance > 1 {
+		if t.heightOf(n.left.left) < t.heightOf(n.left.right) {
+			n.left = t.rotateLeft(t.copyNode(n.left))
+		}
+		return t.rotateRight(n)
+	}
+	if balance < -1 {
+		if t.heightOf(n.right.right) < t.heightOf(n.right.left) {
+			n.right = t.rotateRight(t.copyNode(n.right))
+		}
+		return t.rotateLeft(n)
+	}
+	return n
+}
+
+// insertNode returns the new subtree root, or n itself when nothing
+// changed so that no path is copied.
+func (t *PersistentAVLTree[K, V]) insertNode(n *AVLNode[K, V], key K, value V, replace bool) *AVLNode[K, V] {
+	if n == nil {
+		return &AVLNode[K, V]{key: key, value: value, height: 1, size: 1}
+	}
+
+	if key == n.key {
+		if !replace {
+			return n
+		}
+		c := t.copyNode(n)
+		c.value = value
+		return c
+	}
+
+	c := t.copyNode(n)
+	if key < n.key {
+		left := t.insertNode(n.left, key, value, replace)
+		if left == n.left {
+			return n
+		}
+		c.left = left
+	} else {
+		right := t.insertNode(n.right, key, value, replace)
+		if right == n.right {
+			return n
+		}
+		c.right = right
+	}
+	return t.rebalance(c)
+}
+
+// deleteMin removes the smallest node of n and returns the new subtree
+// together with the removed node.
+func (t *PersistentAVLTree[K, V]) deleteMin(n *AVLNode[K, V]) (*AVLNode[K, V], *AVLNode[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	left, minNode := t.deleteMin(n.left)
+	c := t.copyNode(n)
+	c.left = left
+	return t.rebalance(c), minNode
+}
+
+func (t *PersistentAVLTree[K, V]) deleteNode(n *AVLNode[K, V], key K) *AVLNode[K, V] {
+	if n == nil {
+		return nil
+	}
+
+	if key < n.key {
+		left := t.deleteNode(n.left, key)
+		if left == n.left {
+			return n
+		}
+		c := t.copyNode(n)
+		c.left = left
+		return t.rebalance(c)
+	}
+	if key > n.key {
+		right := t.deleteNode(n.right, key)
+		if right == n.right {
+			return n
+		}
+		c := t.copyNode(n)
+		c.right = right
+		return t.rebalance(c)
+	}
+
+	if n.left == nil {
+		return n.right
+	}
+	if n.right == nil {
+		return n.left
+	}
+	right, successor := t.deleteMin(n.right)
+	c := t.copyNode(n)
+	c.key = successor.key
+	c.value = successor.value
+	c.right = right
+	return t.rebalance(c)
+}
+
+// Insert returns a version containing key. If key is already present the
+// receiver is returned unchanged.
+func (t *PersistentAVLTree[K, V]) Insert(key K) *PersistentAVLTree[K, V] {
+	var zero V
+	root := t.insertNode(t.root, key, zero, false)
+	if root == t.root {
+		return t
+	}
+	return &PersistentAVLTree[K, V]{root: root}
+}
+
+// Put returns a version mapping key to value.
+func (t *PersistentAVLTree[K, V]) Put(key K, value V) *PersistentAVLTree[K, V] {
+	return &PersistentAVLTree[K, V]{root: t.insertNode(t.root, key, value, true)}
+}
+
+// Remove returns a version without key. If key is absent the receiver is
+// returned unchanged.
+func (t *PersistentAVLTree[K, V]) Remove(key K) *PersistentAVLTree[K, V] {
+	root := t.deleteNode(t.root, key)
+	if root == t.root {
+		return t
+	}
+	return &PersistentAVLTree[K, V]{root: root}
+}
+
+func (t *PersistentAVLTree[K, V]) Find(key K) bool {
+	return t.view().Find(key)
+}
+
+func (t *PersistentAVLTree[K, V]) Get(key K) (V, bool) {
+	return t.view().Get(key)
+}
+
+func (t *PersistentAVLTree[K, V]) Len() int {
+	return t.view().Len()
+}
+
+func (t *PersistentAVLTree[K, V]) IsEmpty() bool {
+	return t.root == nil
+}
+
+func (t *PersistentAVLTree[K, V]) Values() []K {
+	return t.view().Values()
+}
+
+func (t *PersistentAVLTree[K, V]) All() iter.Seq2[K, V] {
+	return t.view().All()
+}
+
+func (t *PersistentAVLTree[K, V]) Min() (K, bool) {
+	return t.view().Min()
+}
+
+func (t *PersistentAVLTree[K, V]) Max() (K, bool) {
+	return t.view().Max()
+}
+
+func (t *PersistentAVLTree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return t.view().Range(lo, hi)
+}
+
+func (t *PersistentAVLTree[K, V]) Rank(key K) int {
+	return t.view().Rank(key)
+}
+
+func (t *PersistentAVLTree[K, V]) Select(i int) (K, bool) {
+	return t.view().Select(i)
+}
+
+// Binary Serialization
+
+// SerializeTo writes the version in the AVLTree binary format, so a
+// snapshot can be saved while writers continue.
+func (t *PersistentAVLTree[K, V]) SerializeTo(w io.Writer) error {
+	return t.view().SerializeTo(w)
+}
+
+// DeserializeFrom reads a tree written by AVLTree or PersistentAVLTree and
+// returns it as a new version.
+func (t *PersistentAVLTree[K, V]) DeserializeFrom(r io.Reader) (*PersistentAVLTree[K, V], error) {
+	loaded := NewAVLTreeOf[K, V]()
+	if err := loaded.DeserializeFrom(r); err != nil {
+		return nil, err
+	}
+	return &PersistentAVLTree[K, V]{root: loaded.root}, nil
+}
+
+// Mutable returns an independent AVLTree holding the same entries.
+func (t *PersistentAVLTree[K, V]) Mutable() *AVLTree[K, V] {
+	tree := NewAVLTreeOf[K, V]()
+	for k, v := range t.All() {
+		tree.Put(k, v)
+	}
+	return tree
+}
@@ -0,0 +1,260 @@
+package datastructures
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+)
+
+// PriorityQueue is a binary heap stored in a slice. Pop and Peek return
+// the element that orders first under less, so less(a, b) = a < b gives a
+// min-heap and a > b a max-heap.
+type PriorityQueue[T any] struct {
+	data    []T
+	less    func(a, b T) bool
+	version int
+}
+
+// NewPriorityQueue returns a min-heap of ints.
+func NewPriorityQueue() *PriorityQueue[int] {
+	return NewPriorityQueueOf(func(a, b int) bool { return a < b })
+}
+
+// NewMaxPriorityQueue returns a max-heap of ints.
+func NewMaxPriorityQueue() *PriorityQueue[int] {
+	return NewPriorityQueueOf(func(a, b int) bool { return a > b })
+}
+
+func NewPriorityQueueOf[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	if less == nil {
+		panic("datastructures: PriorityQueue needs a less function")
+	}
+	return &PriorityQueue[T]{less: less}
+}
+
+// NewPriorityQueueFrom builds a heap from a copy of values in O(n).
+func NewPriorityQueueFrom[T any](values []T, less func(a, b T) bool) *PriorityQueue[T] {
+	pq := NewPriorityQueueOf(less)
+	pq.data = append(make([]T, 0, len(values)), values...)
+	pq.heapify()
+	return pq
+}
+
+// heapify restores the heap property bottom-up, which is O(n) rather than
+// the O(n log n) of pushing one by one.
+func (pq *PriorityQueue[T]) heapify() {
+	for i := len(pq.data)/2 - 1; i >= 0; i-- {
+		pq.down(i)
+	}
+	pq.version++
+}
+
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.data[i], pq.data[parent]) {
+			return
+		}
+		pq.data[i], pq.data[parent] = pq.data[parent], pq.data[i]
+		i = parent
+	}
+}
+
+func (pq *PriorityQueue[T]) down(i int) {
+	n := len(pq.data)
+	for {
+		first := i
+		if l := 2*i + 1; l < n && pq.less(pq.data[l], pq.data[first]) {
+			first = l
+		}
+		if r := 2*i + 2; r < n && pq.less(pq.data[r], pq.data[first]) {
+			first = r
+		}
+		if first == i {
+			return
+		}
+		pq.data[i], pq.data[first] = pq.data[first], pq.data[i]
+		i = first
+	}
+}
+
+func (pq *PriorityQueue[T]) Push(value T) {
+	pq.data = append(pq.data, value)
+	pq.up(len(pq.data) - 1)
+	pq.version++
+}
+
+// Pop removes and returns the first element in priority order.
+func (pq *PriorityQueue[T]) Pop() (T, error) {
+	var zero T
+	if len(pq.data) == 0 {
+		return zero, errors.New("priority queue empty")
+	}
+	top := pq.data[0]
+	last := len(pq.data) - 1
+	pq.data[0] = pq.data[last]
+	pq.data[last] = zero
+	pq.data = pq.data[:last]
+	if last > 0 {
+		pq.down(0)
+	}
+	pq.version++
+	return top, nil
+}
+
+func (pq *PriorityQueue[T]) Peek() (T, error) {
+	if len(pq.data) == 0 {
+		var zero T
+		return zero, errors.New("priority queue empty")
+	}
+	return pq.data[0], nil
+}
+
+// Merge moves every element of other into pq and leaves other empty. It
+// re-heapifies in O(n+m) instead of pushing each element.
+func (pq *PriorityQueue[T]) Merge(other *PriorityQueue[T]) {
+	if other == pq || len(other.data) == 0 {
+		return
+	}
+	pq.data = append(pq.data, other.data...)
+	pq.heapify()
+	other.Clear()
+}
+
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.data)
+}
+
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return len(pq.data) == 0
+}
+
+func (pq *PriorityQueue[T]) Clear() {
+	pq.data = nil
+	pq.version++
+}
+
+// Values returns the elements in heap order: the first is the top, the
+// rest are only partially ordered.
+func (pq *PriorityQueue[T]) Values() []T {
+	return append(make([]T, 0, len(pq.data)), pq.data...)
+}
+
+// All yields the elements in heap order.
+func (pq *PriorityQueue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		version := pq.version
+		for _, v := range pq.data {
+			if !yield(v) {
+				return
+			}
+			checkVersion(version, pq.version)
+		}
+	}
+}
+
+func (pq *PriorityQueue[T]) Print() {
+	fmt.Print("PriorityQueue [")
+	for i, v := range pq.data {
+		if i > 0 {
+			fmt.Print(", ")
+		}
+		fmt.Print(v)
+	}
+	fmt.Println("]")
+}
+
+// Binary Serialization
+func (pq *PriorityQueue[T]) Serialize(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	return pq.SerializeTo(file)
+}
+
+// SerializeTo writes the count followed by the elements in heap order.
+func (pq *PriorityQueue[T]) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(pq.data))); err != nil {
+		return err
+	}
+	for _, v := range pq.data {
+		if err := writeValue(w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pq *PriorityQueue[T]) Deserialize(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	return pq.DeserializeFrom(file)
+}
+
+// DeserializeFrom re-heapifies what it reads, so a file written by a queue
+// with a different comparator loads correctly.
+func (pq *PriorityQueue[T]) DeserializeFrom(r io.Reader) error {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return err
+	}
+
+	data := make([]T, 0, min(count, 1<<16))
+	for i := uint64(0); i < count; i++ {
+		value, err := readValue[T](r)
+		if err != nil {
+			return err
+		}
+		data = append(data, value)
+	}
+	pq.data = data
+	pq.heapify()
+	return nil
+}
+
+// JSON Serialization
+type priorityQueueJSON[T any] struct {
+	Data []T `json:"data"`
+}
+
+func (pq *PriorityQueue[T]) SerializeJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	data := priorityQueueJSON[T]{Data: pq.Values()}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (pq *PriorityQueue[T]) DeserializeJSON(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	var data priorityQueueJSON[T]
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+
+	pq.data = data.Data
+	pq.heapify()
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 )
 
@@ -16,14 +18,32 @@ type QueueNode struct {
 type MyQueue struct {
 	frontNode *QueueNode
 	rearNode  *QueueNode
+	size      int
+	version   int
+	// items holds the elements instead of the node chain when the queue
+	// was built by NewMyQueueOnDeque.
+	items *Deque[int]
 }
 
 func NewMyQueue() *MyQueue {
 	return &MyQueue{frontNode: nil, rearNode: nil}
 }
 
+// NewMyQueueOnDeque returns a queue stored in a ring-buffer Deque, so Push
+// does not allocate a node. It behaves and serializes exactly like a
+// NewMyQueue queue.
+func NewMyQueueOnDeque() *MyQueue {
+	return &MyQueue{items: NewDeque()}
+}
+
 func (q *MyQueue) Push(value int) {
+	if q.items != nil {
+		q.items.PushBack(value)
+		return
+	}
 	newNode := &QueueNode{data: value, next: nil}
+	q.size++
+	q.version++
 	if q.rearNode == nil {
 		q.frontNode = newNode
 		q.rearNode = newNode
@@ -34,31 +54,106 @@ func (q *MyQueue) Push(value int) {
 }
 
 func (q *MyQueue) Pop() {
+	if q.items != nil {
+		q.items.PopFront()
+		return
+	}
 	if q.frontNode == nil {
 		return
 	}
 	q.frontNode = q.frontNode.next
+	q.size--
+	q.version++
 	if q.frontNode == nil {
 		q.rearNode = nil
 	}
 }
 
 func (q *MyQueue) Peek() (int, error) {
+	if q.items != nil {
+		if q.items.IsEmpty() {
+			return 0, errors.New("queue empty")
+		}
+		return q.items.Front()
+	}
 	if q.frontNode == nil {
 		return 0, errors.New("queue empty")
 	}
 	return q.frontNode.data, nil
 }
 
+func (q *MyQueue) Len() int {
+	if q.items != nil {
+		return q.items.Len()
+	}
+	return q.size
+}
+
+func (q *MyQueue) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+func (q *MyQueue) Clear() {
+	if q.items != nil {
+		q.items.Clear()
+		return
+	}
+	q.frontNode = nil
+	q.rearNode = nil
+	q.size = 0
+	q.version++
+}
+
+// Values returns the elements from front to back.
+func (q *MyQueue) Values() []int {
+	if q.items != nil {
+		return q.items.Values()
+	}
+	values := make([]int, 0, q.size)
+	for curr := q.frontNode; curr != nil; curr = curr.next {
+		values = append(values, curr.data)
+	}
+	return values
+}
+
+// At returns the element index positions behind the front.
+func (q *MyQueue) At(index int) (int, error) {
+	if q.items != nil {
+		return q.items.At(index)
+	}
+	if index < 0 || index >= q.size {
+		return 0, errors.New("index out of bounds")
+	}
+	curr := q.frontNode
+	for i := 0; i < index; i++ {
+		curr = curr.next
+	}
+	return curr.data, nil
+}
+
+// All yields the elements from front to back.
+func (q *MyQueue) All() iter.Seq[int] {
+	if q.items != nil {
+		return q.items.All()
+	}
+	return func(yield func(int) bool) {
+		version := q.version
+		for curr := q.frontNode; curr != nil; curr = curr.next {
+			if !yield(curr.data) {
+				return
+			}
+			checkVersion(version, q.version)
+		}
+	}
+}
+
 func (q *MyQueue) Print() {
 	fmt.Print("Queue [")
-	curr := q.frontNode
-	for curr != nil {
-		fmt.Print(curr.data)
-		if curr.next != nil {
+	for i, v := range q.Values() {
+		if i > 0 {
 			fmt.Print(" -> ")
 		}
-		curr = curr.next
+		fmt.Print(v)
 	}
 	fmt.Println("]")
 }
@@ -71,23 +166,18 @@ func (q *MyQueue) Serialize(filename string) error {
 	}
 	defer file.Close()
 
-	count := uint64(0)
-	curr := q.frontNode
-	for curr != nil {
-		count++
-		curr = curr.next
-	}
+	return q.SerializeTo(file)
+}
 
-	if err := binary.Write(file, binary.LittleEndian, count); err != nil {
+func (q *MyQueue) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(q.Len())); err != nil {
 		return err
 	}
 
-	curr = q.frontNode
-	for curr != nil {
-		if err := binary.Write(file, binary.LittleEndian, int32(curr.data)); err != nil {
+	for v := range q.All() {
+		if err := binary.Write(w, binary.LittleEndian, int32(v)); err != nil {
 			return err
 		}
-		curr = curr.next
 	}
 	return nil
 }
@@ -99,19 +189,20 @@ func (q *MyQueue) Deserialize(filename string) error {
 	}
 	defer file.Close()
 
-	// Clear existing queue
-	for q.frontNode != nil {
-		q.Pop()
-	}
+	return q.DeserializeFrom(file)
+}
+
+func (q *MyQueue) DeserializeFrom(r io.Reader) error {
+	q.Clear()
 
 	var count uint64
-	if err := binary.Read(file, binary.LittleEndian, &count); err != nil {
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
 		return err
 	}
 
 	for i := uint64(0); i < count; i++ {
 		var value int32
-		if err := binary.Read(file, binary.LittleEndian, &value); err != nil {
+		if err := binary.Read(r, binary.LittleEndian, &value); err != nil {
 			return err
 		}
 		q.Push(int(value))
@@ -131,14 +222,7 @@ func (q *MyQueue) SerializeJSON(filename string) error {
 	}
 	defer file.Close()
 
-	data := make([]int, 0)
-	curr := q.frontNode
-	for curr != nil {
-		data = append(data, curr.data)
-		curr = curr.next
-	}
-
-	queueData := queueJSON{Data: data}
+	queueData := queueJSON{Data: q.Values()}
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(queueData)
@@ -151,10 +235,7 @@ func (q *MyQueue) DeserializeJSON(filename string) error {
 	}
 	defer file.Close()
 
-	// Clear existing queue
-	for q.frontNode != nil {
-		q.Pop()
-	}
+	q.Clear()
 
 	var queueData queueJSON
 	decoder := json.NewDecoder(file)
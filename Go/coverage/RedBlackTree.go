@@ -0,0 +1,642 @@
+package datastructures
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"math/bits"
+	"os"
+)
+
+type rbNode[K cmp.Ordered, V any] struct {
+	key    K
+	value  V
+	left   *rbNode[K, V]
+	right  *rbNode[K, V]
+	parent *rbNode[K, V]
+	red    bool // nil children count as black
+}
+
+// RedBlackTree is a sorted map from K to V with the same API as AVLTree;
+// with V = struct{} it is an ordered set. It keeps every root-to-leaf path
+// at the same number of black nodes and never puts two red nodes in a
+// row, so it is less strictly balanced than an AVL tree (height up to
+// 2 log n) but repairs an insert with at most two rotations and a delete
+// with at most three. Most fixes are recolorings; Stats counts both.
+type RedBlackTree[K cmp.Ordered, V any] struct {
+	root    *rbNode[K, V]
+	size    int
+	version int
+	stats   TreeStats
+}
+
+func NewRedBlackTree() *RedBlackTree[int, struct{}] {
+	return NewRedBlackTreeOf[int, struct{}]()
+}
+
+func NewRedBlackTreeOf[K cmp.Ordered, V any]() *RedBlackTree[K, V] {
+	return &RedBlackTree[K, V]{}
+}
+
+// storesValues reports whether V carries data, as for AVLTree.
+func (t *RedBlackTree[K, V]) storesValues() bool {
+	_, isSet := any(*new(V)).(struct{})
+	return !isSet
+}
+
+func isRed[K cmp.Ordered, V any](n *rbNode[K, V]) bool {
+	return n != nil && n.red
+}
+
+// paint sets the color of n and counts it if it changed.
+func (t *RedBlackTree[K, V]) paint(n *rbNode[K, V], red bool) {
+	if n.red != red {
+		n.red = red
+		t.stats.Recolorings++
+	}
+}
+
+// replaceChild points the parent of old, or the root, at n.
+func (t *RedBlackTree[K, V]) replaceChild(old, n *rbNode[K, V]) {
+	switch {
+	case old.parent == nil:
+		t.root = n
+	case old == old.parent.left:
+		old.parent.left = n
+	default:
+		old.parent.right = n
+	}
+	if n != nil {
+		n.parent = old.parent
+	}
+}
+
+func (t *RedBlackTree[K, V]) rotateLeft(x *rbNode[K, V]) {
+	t.stats.Rotations++
+	y := x.right
+	x.right = y.left
+	if y.left != nil {
+		y.left.parent = x
+	}
+	t.replaceChild(x, y)
+	y.left = x
+	x.parent = y
+}
+
+func (t *RedBlackTree[K, V]) rotateRight(x *rbNode[K, V]) {
+	t.stats.Rotations++
+	y := x.left
+	x.left = y.right
+	if y.right != nil {
+		y.right.parent = x
+	}
+	t.replaceChild(x, y)
+	y.right = x
+	x.parent = y
+}
+
+func (t *RedBlackTree[K, V]) findNode(key K) *rbNode[K, V] {
+	curr := t.root
+	for curr != nil {
+		if key == curr.key {
+			return curr
+		}
+		if key < curr.key {
+			curr = curr.left
+		} else {
+			curr = curr.right
+		}
+	}
+	return nil
+}
+
+func (t *RedBlackTree[K, V]) put(key K, value V, replace bool) {
+	var parent *rbNode[K, V]
+	curr := t.root
+	for curr != nil {
+		if key == curr.key {
+			if replace {
+				curr.value = value
+			}
+			return
+		}
+		parent = curr
+		if key < curr.key {
+			curr = curr.left
+		} else {
+			curr = curr.right
+		}
+	}
+
+	node := &rbNode[K, V]{key: key, value: value, parent: parent, red: true}
+	switch {
+	case parent == nil:
+		t.root = node
+	case key < parent.key:
+		parent.left = node
+	default:
+		parent.right = node
+	}
+	t.insertFixup(node)
+	t.size++
+	t.version++
+}
+
+// insertFixup removes the red-red violation a new red node z may cause.
+// A red uncle is recolored and the problem moves two levels up; a black
+// uncle ends it with one or two rotations.
+func (t *RedBlackTree[K, V]) insertFixup(z *rbNode[K, V]) {
+	for isRed(z.parent) {
+		parent := z.parent
+		grand := parent.parent // exists: a red node is never the root
+		if parent == grand.left {
+			if uncle := grand.right; isRed(uncle) {
+				t.paint(parent, false)
+				t.paint(uncle, false)
+				t.paint(grand, true)
+				z = grand
+				continue
+			}
+			if z == parent.right {
+				t.rotateLeft(parent)
+				z, parent = parent, z
+			}
+			t.paint(parent, false)
+			t.paint(grand, true)
+			t.rotateRight(grand)
+		} else {
+			if uncle := grand.left; isRed(uncle) {
+				t.paint(parent, false)
+				t.paint(uncle, false)
+				t.paint(grand, true)
+				z = grand
+				continue
+			}
+			if z == parent.left {
+				t.rotateRight(parent)
+				z, parent = parent, z
+			}
+			t.paint(parent, false)
+			t.paint(grand, true)
+			t.rotateLeft(grand)
+		}
+	}
+	t.paint(t.root, false)
+}
+
+func (t *RedBlackTree[K, V]) deleteNode(z *rbNode[K, V]) {
+	// x takes the place of the node that leaves its position; it may be
+	// nil, so its parent is tracked separately.
+	var x, xParent *rbNode[K, V]
+	removedRed := z.red
+	switch {
+	case z.left == nil:
+		x, xParent = z.right, z.parent
+		t.replaceChild(z, z.right)
+	case z.right == nil:
+		x, xParent = z.left, z.parent
+		t.replaceChild(z, z.left)
+	default:
+		y := z.right
+		for y.left != nil {
+			y = y.left
+		}
+		removedRed = y.red
+		x = y.right
+		if y.parent == z {
+			xParent = y
+		} else {
+			xParent = y.parent
+			t.replaceChild(y, y.right)
+			y.right = z.right
+			y.right.parent = y
+		}
+		t.replaceChild(z, y)
+		y.left = z.left
+		y.left.parent = y
+		// y takes z's place and color; this is a move, not a recoloring
+		y.red = z.red
+	}
+	if !removedRed {
+		t.deleteFixup(x, xParent)
+	}
+}
+
+// deleteFixup restores the black height after a black node was removed
+// above x, which now counts one black short.
+func (t *RedBlackTree[K, V]) deleteFixup(x, parent *rbNode[K, V]) {
+	for x != t.root && !isRed(x) {
+		if x == parent.left {
+			sibling := parent.right
+			if isRed(sibling) {
+				t.paint(sibling, false)
+				t.paint(parent, true)
+				t.rotateLeft(parent)
+				sibling = parent.right
+			}
+			if !isRed(sibling.left) && !isRed(sibling.right) {
+				t.paint(sibling, true)
+				x, parent = parent, parent.parent
+				continue
+			}
+			if !isRed(sibling.right) {
+				t.paint(sibling.left, false)
+				t.paint(sibling, true)
+				t.rotateRight(sibling)
+				sibling = parent.right
+			}
+			t.paint(sibling, parent.red)
+			t.paint(parent, false)
+			t.paint(sibling.right, false)
+			t.rotateLeft(parent)
+		} else {
+			sibling := parent.left
+			if isRed(sibling) {
+				t.paint(sibling, false)
+				t.paint(parent, true)
+				t.rotateRight(parent)
+				sibling = parent.left
+			}
+			if !isRed(sibling.left) && !isRed(sibling.right) {
+				t.paint(sibling, true)
+				x, parent = parent, parent.parent
+				continue
+			}
+			if !isRed(sibling.left) {
+				t.paint(sibling.right, false)
+				t.paint(sibling, true)
+				t.rotateLeft(sibling)
+				sibling = parent.left
+			}
+			t.paint(sibling, parent.red)
+			t.paint(parent, false)
+			t.paint(sibling.left, false)
+			t.rotateRight(parent)
+		}
+		x = t.root
+	}
+	if x != nil {
+		t.paint(x, false)
+	}
+}
+
+// Insert adds key with a zero value. An existing key keeps its value.
+func (t *RedBlackTree[K, V]) Insert(key K) {
+	t.put(key, *new(V), false)
+}
+
+// Put stores value under key, replacing the value of an existing key.
+func (t *RedBlackTree[K, V]) Put(key K, value V) {
+	t.put(key, value, true)
+}
+
+func (t *RedBlackTree[K, V]) Get(key K) (V, bool) {
+	if node := t.findNode(key); node != nil {
+		return node.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (t *RedBlackTree[K, V]) Find(key K) bool {
+	return t.findNode(key) != nil
+}
+
+func (t *RedBlackTree[K, V]) Remove(key K) {
+	t.Delete(key)
+}
+
+// Delete removes key and reports whether it was present.
+func (t *RedBlackTree[K, V]) Delete(key K) bool {
+	node := t.findNode(key)
+	if node == nil {
+		return false
+	}
+	t.deleteNode(node)
+	t.size--
+	t.version++
+	return true
+}
+
+func (t *RedBlackTree[K, V]) Len() int {
+	return t.size
+}
+
+func (t *RedBlackTree[K, V]) IsEmpty() bool {
+	return t.root == nil
+}
+
+func (t *RedBlackTree[K, V]) Clear() {
+	t.root = nil
+	t.size = 0
+	t.version++
+}
+
+// Stats returns the rotations and recolorings done since the tree was
+// created or ResetStats was called. Loading a file does neither.
+func (t *RedBlackTree[K, V]) Stats() TreeStats {
+	return t.stats
+}
+
+func (t *RedBlackTree[K, V]) ResetStats() {
+	t.stats = TreeStats{}
+}
+
+// Height returns the number of nodes on the longest root-to-leaf path.
+func (t *RedBlackTree[K, V]) Height() int {
+	var height func(n *rbNode[K, V]) int
+	height = func(n *rbNode[K, V]) int {
+		if n == nil {
+			return 0
+		}
+		return 1 + max(height(n.left), height(n.right))
+	}
+	return height(t.root)
+}
+
+// Values returns the keys in ascending order, like AVLTree.Values.
+func (t *RedBlackTree[K, V]) Values() []K {
+	keys := make([]K, 0, t.size)
+	for key := range t.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ascend yields the entries from n on, in order, while within holds.
+func (t *RedBlackTree[K, V]) ascend(n *rbNode[K, V], within func(K) bool, yield func(K, V) bool) {
+	version := t.version
+	for n != nil && within(n.key) {
+		if !yield(n.key, n.value) {
+			return
+		}
+		checkVersion(version, t.version)
+		n = t.successor(n)
+	}
+}
+
+// successor returns the next node in key order by following parent links.
+func (t *RedBlackTree[K, V]) successor(n *rbNode[K, V]) *rbNode[K, V] {
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n
+	}
+	for n.parent != nil && n == n.parent.right {
+		n = n.parent
+	}
+	return n.parent
+}
+
+func (t *RedBlackTree[K, V]) first() *rbNode[K, V] {
+	n := t.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+// All yields key/value pairs in ascending key order.
+func (t *RedBlackTree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.ascend(t.first(), func(K) bool { return true }, yield)
+	}
+}
+
+// Range yields the key/value pairs with lo <= key <= hi in ascending order.
+func (t *RedBlackTree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.ascend(t.ceilingNode(lo), func(key K) bool { return key <= hi }, yield)
+	}
+}
+
+func (t *RedBlackTree[K, V]) Min() (K, bool) {
+	if n := t.first(); n != nil {
+		return n.key, true
+	}
+	var zero K
+	return zero, false
+}
+
+func (t *RedBlackTree[K, V]) Max() (K, bool) {
+	n := t.root
+	if n == nil {
+		var zero K
+		return zero, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, true
+}
+
+// Floor returns the largest key <= key.
+func (t *RedBlackTree[K, V]) Floor(key K) (K, bool) {
+	var best *rbNode[K, V]
+	for curr := t.root; curr != nil; {
+		if curr.key <= key {
+			best = curr
+			curr = curr.right
+		} else {
+			curr = curr.left
+		}
+	}
+	if best == nil {
+		var zero K
+		return zero, false
+	}
+	return best.key, true
+}
+
+func (t *RedBlackTree[K, V]) ceilingNode(key K) *rbNode[K, V] {
+	var best *rbNode[K, V]
+	for curr := t.root; curr != nil; {
+		if curr.key >= key {
+			best = curr
+			curr = curr.left
+		} else {
+			curr = curr.right
+		}
+	}
+	return best
+}
+
+// Ceiling returns the smallest key >= key.
+func (t *RedBlackTree[K, V]) Ceiling(key K) (K, bool) {
+	if n := t.ceilingNode(key); n != nil {
+		return n.key, true
+	}
+	var zero K
+	return zero, false
+}
+
+func (t *RedBlackTree[K, V]) Print() {
+	fmt.Print("RedBlackTree (In-order): ")
+	for key, value := range t.All() {
+		if t.storesValues() {
+			fmt.Print(key, ":", value, " ")
+		} else {
+			fmt.Print(key, " ")
+		}
+	}
+	fmt.Println()
+}
+
+// load replaces the contents with keys and values, which may be nil for a
+// set. Strictly ascending keys, as the serializers write them, are built
+// into a balanced tree in O(n) without rotations: every node is black
+// except those on the last level when it is incomplete. Other input is
+// inserted one key at a time.
+func (t *RedBlackTree[K, V]) load(keys []K, values []V) {
+	t.Clear()
+	valueAt := func(i int) V {
+		if values == nil {
+			return *new(V)
+		}
+		return values[i]
+	}
+
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] >= keys[i] {
+			stats := t.stats
+			for j, key := range keys {
+				t.Put(key, valueAt(j))
+			}
+			t.stats = stats
+			return
+		}
+	}
+
+	redDepth := -1
+	if n := len(keys); n&(n+1) != 0 {
+		redDepth = bits.Len(uint(n)) - 1
+	}
+	var build func(lo, hi, depth int, parent *rbNode[K, V]) *rbNode[K, V]
+	build = func(lo, hi, depth int, parent *rbNode[K, V]) *rbNode[K, V] {
+		if lo >= hi {
+			return nil
+		}
+		mid := (lo + hi) / 2
+		n := &rbNode[K, V]{key: keys[mid], value: valueAt(mid), parent: parent, red: depth == redDepth}
+		n.left = build(lo, mid, depth+1, n)
+		n.right = build(mid+1, hi, depth+1, n)
+		return n
+	}
+	t.root = build(0, len(keys), 0, nil)
+	t.size = len(keys)
+}
+
+// Binary Serialization
+func (t *RedBlackTree[K, V]) Serialize(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	return t.SerializeTo(file)
+}
+
+// SerializeTo writes the count followed by key/value pairs in ascending
+// order. Colors are not stored: load rebuilds a balanced tree.
+func (t *RedBlackTree[K, V]) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(t.size)); err != nil {
+		return err
+	}
+	for key, value := range t.All() {
+		if err := writeValue(w, key); err != nil {
+			return err
+		}
+		if err := writeValue(w, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *RedBlackTree[K, V]) Deserialize(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	return t.DeserializeFrom(file)
+}
+
+func (t *RedBlackTree[K, V]) DeserializeFrom(r io.Reader) error {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return err
+	}
+
+	keys := make([]K, 0, min(count, 1<<16))
+	values := make([]V, 0, min(count, 1<<16))
+	for i := uint64(0); i < count; i++ {
+		key, err := readValue[K](r)
+		if err != nil {
+			return err
+		}
+		value, err := readValue[V](r)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	t.load(keys, values)
+	return nil
+}
+
+// JSON Serialization
+type redBlackTreeJSON[K cmp.Ordered, V any] struct {
+	Keys   []K `json:"keys"`
+	Values []V `json:"values,omitempty"` // left out for sets
+}
+
+func (t *RedBlackTree[K, V]) SerializeJSON(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for writing: %w", err)
+	}
+	defer file.Close()
+
+	data := redBlackTreeJSON[K, V]{Keys: t.Values()}
+	if t.storesValues() {
+		data.Values = make([]V, 0, t.size)
+		for _, value := range t.All() {
+			data.Values = append(data.Values, value)
+		}
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (t *RedBlackTree[K, V]) DeserializeJSON(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("cannot open file for reading: %w", err)
+	}
+	defer file.Close()
+
+	var data redBlackTreeJSON[K, V]
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+	if len(data.Values) != 0 && len(data.Values) != len(data.Keys) {
+		return fmt.Errorf("red-black tree json: %d keys but %d values", len(data.Keys), len(data.Values))
+	}
+	if len(data.Values) == 0 {
+		data.Values = nil
+	}
+
+	t.load(data.Keys, data.Values)
+	return nil
+}
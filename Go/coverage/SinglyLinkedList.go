@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 )
 
@@ -14,9 +16,10 @@ type SNode struct {
 }
 
 type SinglyLinkedList struct {
-	head *SNode
-	tail *SNode
-	size int
+	head    *SNode
+	tail    *SNode
+	size    int
+	version int
 }
 
 func NewSinglyLinkedList() *SinglyLinkedList {
@@ -34,6 +37,7 @@ func (s *SinglyLinkedList) PushFront(value int) {
 		s.tail = s.head
 	}
 	s.size++
+	s.version++
 }
 
 func (s *SinglyLinkedList) PushBack(value int) {
@@ -46,6 +50,7 @@ func (s *SinglyLinkedList) PushBack(value int) {
 		s.tail = newNode
 	}
 	s.size++
+	s.version++
 }
 
 func (s *SinglyLinkedList) InsertAfter(index int, value int) error {
@@ -62,6 +67,7 @@ func (s *SinglyLinkedList) InsertAfter(index int, value int) error {
 		s.tail = newNode
 	}
 	s.size++
+	s.version++
 	return nil
 }
 
@@ -85,6 +91,7 @@ func (s *SinglyLinkedList) PopFront() {
 		s.tail = nil
 	}
 	s.size--
+	s.version++
 }
 
 func (s *SinglyLinkedList) PopBack() {
@@ -95,6 +102,7 @@ func (s *SinglyLinkedList) PopBack() {
 		s.head = nil
 		s.tail = nil
 		s.size = 0
+		s.version++
 		return
 	}
 	curr := s.head
@@ -104,6 +112,7 @@ func (s *SinglyLinkedList) PopBack() {
 	s.tail = curr
 	s.tail.next = nil
 	s.size--
+	s.version++
 }
 
 func (s *SinglyLinkedList) RemoveAt(index int) error {
@@ -125,6 +134,7 @@ func (s *SinglyLinkedList) RemoveAt(index int) error {
 	}
 	toDel = nil
 	s.size--
+	s.version++
 	return nil
 }
 
@@ -148,6 +158,7 @@ func (s *SinglyLinkedList) RemoveByValue(value int) {
 		}
 		toDel = nil
 		s.size--
+		s.version++
 	}
 }
 
@@ -166,6 +177,55 @@ func (s *SinglyLinkedList) GetSize() int {
 	return s.size
 }
 
+func (s *SinglyLinkedList) Len() int {
+	return s.size
+}
+
+func (s *SinglyLinkedList) IsEmpty() bool {
+	return s.size == 0
+}
+
+func (s *SinglyLinkedList) Clear() {
+	s.head = nil
+	s.tail = nil
+	s.size = 0
+	s.version++
+}
+
+func (s *SinglyLinkedList) Values() []int {
+	values := make([]int, 0, s.size)
+	for curr := s.head; curr != nil; curr = curr.next {
+		values = append(values, curr.data)
+	}
+	return values
+}
+
+func (s *SinglyLinkedList) At(index int) (int, error) {
+	if index < 0 || index >= s.size {
+		return 0, errors.New("index out of bounds")
+	}
+	curr := s.head
+	for i := 0; i < index; i++ {
+		curr = curr.next
+	}
+	return curr.data, nil
+}
+
+// All yields index/value pairs from head to tail.
+func (s *SinglyLinkedList) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		version := s.version
+		i := 0
+		for curr := s.head; curr != nil; curr = curr.next {
+			if !yield(i, curr.data) {
+				return
+			}
+			checkVersion(version, s.version)
+			i++
+		}
+	}
+}
+
 func (s *SinglyLinkedList) Print() {
 	fmt.Print("SinglyLinkedList [")
 	curr := s.head
@@ -187,12 +247,16 @@ func (s *SinglyLinkedList) Serialize(filename string) error {
 	}
 	defer file.Close()
 
-	if err := binary.Write(file, binary.LittleEndian, uint64(s.size)); err != nil {
+	return s.SerializeTo(file)
+}
+
+func (s *SinglyLinkedList) SerializeTo(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(s.size)); err != nil {
 		return err
 	}
 	curr := s.head
 	for curr != nil {
-		if err := binary.Write(file, binary.LittleEndian, int32(curr.data)); err != nil {
+		if err := binary.Write(w, binary.LittleEndian, int32(curr.data)); err != nil {
 			return err
 		}
 		curr = curr.next
@@ -207,13 +271,18 @@ func (s *SinglyLinkedList) Deserialize(filename string) error {
 	}
 	defer file.Close()
 
+	return s.DeserializeFrom(file)
+}
+
+func (s *SinglyLinkedList) DeserializeFrom(r io.Reader) error {
 	// Clear existing list
 	s.head = nil
 	s.tail = nil
 	s.size = 0
+	s.version++
 
 	var fileSize uint64
-	if err := binary.Read(file, binary.LittleEndian, &fileSize); err != nil {
+	if err := binary.Read(r, binary.LittleEndian, &fileSize); err != nil {
 		return err
 	}
 
@@ -223,7 +292,7 @@ func (s *SinglyLinkedList) Deserialize(filename string) error {
 
 	for i := uint64(0); i < fileSize; i++ {
 		var value int32
-		if err := binary.Read(file, binary.LittleEndian, &value); err != nil {
+		if err := binary.Read(r, binary.LittleEndian, &value); err != nil {
 			return err
 		}
 		s.PushBack(int(value))
@@ -267,6 +336,7 @@ func (s *SinglyLinkedList) DeserializeJSON(filename string) error {
 	s.head = nil
 	s.tail = nil
 	s.size = 0
+	s.version++
 
 	var listData singlyListJSON
 	decoder := json.NewDecoder(file)
@@ -1,8 +1,21 @@
 package datastructures
 
 import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"math/bits"
+	"math/rand"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -216,6 +229,70 @@ func TestMyArray_Resize(t *testing.T) {
 	assert.True(t, arr.capacity >= 100)
 }
 
+func TestMyArrayOf_Strings(t *testing.T) {
+	arr := NewMyArrayOf[string]()
+	arr.AddToEnd("b")
+	arr.AddToEnd("c")
+	assert.NoError(t, arr.AddAtIndex(0, "a"))
+	assert.NoError(t, arr.ReplaceAtIndex(2, "z"))
+
+	val, err := arr.GetAtIndex(2)
+	assert.NoError(t, err)
+	assert.Equal(t, "z", val)
+
+	_, err = arr.GetAtIndex(3)
+	assert.Error(t, err)
+	arr.Print()
+}
+
+func TestMyArrayOf_SerializeGeneric(t *testing.T) {
+	type point struct {
+		X, Y float64
+	}
+	type named struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+
+	points := NewMyArrayOf[point]()
+	points.AddToEnd(point{1.5, -2})
+	points.AddToEnd(point{3, 4.25})
+
+	names := NewMyArrayOf[named]()
+	names.AddToEnd(named{Name: "first", Tags: []string{"a"}})
+	names.AddToEnd(named{Name: "second"})
+
+	strs := NewMyArrayOf[string]()
+	strs.AddToEnd("hello")
+	strs.AddToEnd("")
+
+	filename := "test_array_generic.bin"
+	defer os.Remove(filename)
+
+	require.NoError(t, points.Serialize(filename))
+	points2 := NewMyArrayOf[point]()
+	require.NoError(t, points2.Deserialize(filename))
+	assert.Equal(t, points.data[:points.size], points2.data[:points2.size])
+
+	require.NoError(t, names.Serialize(filename))
+	names2 := NewMyArrayOf[named]()
+	require.NoError(t, names2.Deserialize(filename))
+	assert.Equal(t, names.data[:names.size], names2.data[:names2.size])
+
+	require.NoError(t, strs.Serialize(filename))
+	strs2 := NewMyArrayOf[string]()
+	require.NoError(t, strs2.Deserialize(filename))
+	assert.Equal(t, strs.data[:strs.size], strs2.data[:strs2.size])
+
+	jsonFile := "test_array_generic.json"
+	defer os.Remove(jsonFile)
+
+	require.NoError(t, names.SerializeJSON(jsonFile))
+	names3 := NewMyArrayOf[named]()
+	require.NoError(t, names3.DeserializeJSON(jsonFile))
+	assert.Equal(t, names.data[:names.size], names3.data[:names3.size])
+}
+
 // ==================== Stack Tests ====================
 
 func TestNewMyStack(t *testing.T) {
@@ -934,6 +1011,95 @@ func TestDoublyLinkedList_SerializeErrors(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDoublyLinkedList_NodeHandles(t *testing.T) {
+	list := NewDoublyLinkedList()
+	a := list.PushBack(1)
+	b := list.PushBack(2)
+	c := list.PushBack(3)
+	assert.Equal(t, a, list.Front())
+	assert.Equal(t, c, list.Back())
+	assert.Equal(t, b, a.Next())
+	assert.Equal(t, b, c.Prev())
+	assert.Nil(t, a.Prev())
+
+	require.NoError(t, list.MoveToFront(c))
+	assert.Equal(t, []int{3, 1, 2}, list.Values())
+	require.NoError(t, list.MoveToBack(c))
+	assert.Equal(t, []int{1, 2, 3}, list.Values())
+	require.NoError(t, list.MoveToFront(a))
+	assert.Equal(t, []int{1, 2, 3}, list.Values())
+
+	require.NoError(t, list.RemoveNode(b))
+	assert.Equal(t, []int{1, 3}, list.Values())
+	assert.Equal(t, 2, list.GetSize())
+	assert.Equal(t, c, a.Next())
+	assert.Error(t, list.RemoveNode(b))
+	assert.Error(t, list.MoveToFront(b))
+	assert.Error(t, list.MoveToBack(nil))
+
+	other := NewDoublyLinkedList()
+	assert.Error(t, other.RemoveNode(a))
+
+	// Nodes unlinked by the index and value methods are detached too
+	list.PopFront()
+	assert.Error(t, list.MoveToFront(a))
+	d := list.PushFront(4)
+	list.RemoveByValue(4)
+	assert.Error(t, list.RemoveNode(d))
+	assert.Equal(t, c, list.Front())
+	assert.Equal(t, c, list.Back())
+
+	e, err := list.InsertAfterNode(c, 5)
+	require.NoError(t, err)
+	assert.Equal(t, e, list.Back())
+	f, err := list.InsertAfterNode(c, 6)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 6, 5}, list.Values())
+	assert.Equal(t, f, e.Prev())
+	require.NoError(t, list.RemoveNode(f))
+	require.NoError(t, list.RemoveNode(e))
+	_, err = list.InsertAfterNode(e, 7)
+	assert.Error(t, err)
+
+	require.NoError(t, list.RemoveNode(c))
+	assert.Nil(t, list.Front())
+	assert.Nil(t, list.Back())
+	assert.True(t, list.IsEmpty())
+}
+
+func TestDoublyLinkedList_HandleAfterClear(t *testing.T) {
+	list := NewDoublyLinkedList()
+	head := list.PushBack(1)
+	tail := list.PushBack(2)
+	list.Clear()
+
+	assert.Error(t, list.RemoveNode(head))
+	assert.Error(t, list.MoveToFront(tail))
+	assert.Error(t, list.MoveToBack(head))
+	_, err := list.InsertAfterNode(tail, 3)
+	assert.Error(t, err)
+	assert.Equal(t, 0, list.Len())
+	assert.Nil(t, list.Front())
+	assert.Nil(t, head.Next())
+
+	// Loading a file drops the old nodes the same way
+	n := list.PushBack(5)
+	filename := "test_doubly_handles.json"
+	defer os.Remove(filename)
+	require.NoError(t, list.SerializeJSON(filename))
+	require.NoError(t, list.DeserializeJSON(filename))
+	assert.Error(t, list.RemoveNode(n))
+	assert.Equal(t, []int{5}, list.Values())
+
+	binFile := "test_doubly_handles.bin"
+	defer os.Remove(binFile)
+	n = list.Front()
+	require.NoError(t, list.Serialize(binFile))
+	require.NoError(t, list.Deserialize(binFile))
+	assert.Error(t, list.MoveToBack(n))
+	assert.Equal(t, 1, list.Len())
+}
+
 
 
 // ==================== HashTableChain Tests ====================
@@ -1307,6 +1473,126 @@ func TestHashTableOpen_FullTableProbing(t *testing.T) {
 	}
 }
 
+// ==================== Generic Hash Table Tests ====================
+
+type compositeID struct {
+	Region string
+	Seq    int
+}
+
+func TestHashTableChainOf_StringKeys(t *testing.T) {
+	ht := NewHashTableChainOf[string, float64](4, nil, nil)
+	ht.Insert("pi", 3.14)
+	ht.Insert("e", 2.71)
+	ht.Insert("phi", 1.61)
+
+	val, found := ht.Get("e")
+	assert.True(t, found)
+	assert.Equal(t, 2.71, val)
+
+	ht.Remove("e")
+	_, found = ht.Get("e")
+	assert.False(t, found)
+
+	filename := "test_htchain_strings.bin"
+	defer os.Remove(filename)
+	require.NoError(t, ht.Serialize(filename))
+
+	ht2 := NewHashTableChainOf[string, float64](4, nil, nil)
+	require.NoError(t, ht2.Deserialize(filename))
+	val, found = ht2.Get("phi")
+	assert.True(t, found)
+	assert.Equal(t, 1.61, val)
+	ht2.Print()
+}
+
+func TestHashTableChainOf_ByteSliceKeys(t *testing.T) {
+	ht := NewHashTableChainOf[[]byte, int](8, nil, nil)
+	ht.Insert([]byte("alpha"), 1)
+	ht.Insert([]byte("beta"), 2)
+
+	// Lookups compare content, not slice identity
+	val, found := ht.Get([]byte("alpha"))
+	assert.True(t, found)
+	assert.Equal(t, 1, val)
+}
+
+func TestHashTableChainOf_StructKeys(t *testing.T) {
+	hasher := func(id compositeID) uint64 {
+		return uint64(len(id.Region))*31 + uint64(id.Seq)
+	}
+	ht := NewHashTableChainOf[compositeID, string](8, hasher, nil)
+	ht.Insert(compositeID{"eu", 1}, "first")
+	ht.Insert(compositeID{"us", 1}, "second")
+
+	val, found := ht.Get(compositeID{"us", 1})
+	assert.True(t, found)
+	assert.Equal(t, "second", val)
+
+	_, found = ht.Get(compositeID{"us", 2})
+	assert.False(t, found)
+
+	filename := "test_htchain_struct.json"
+	defer os.Remove(filename)
+	require.NoError(t, ht.SerializeJSON(filename))
+
+	ht2 := NewHashTableChainOf[compositeID, string](8, hasher, nil)
+	require.NoError(t, ht2.DeserializeJSON(filename))
+	val, found = ht2.Get(compositeID{"eu", 1})
+	assert.True(t, found)
+	assert.Equal(t, "first", val)
+}
+
+func TestHashTableChainOf_MissingHasher(t *testing.T) {
+	assert.Panics(t, func() {
+		NewHashTableChainOf[compositeID, int](8, nil, nil)
+	})
+}
+
+func TestHashTableOpenOf_StringKeys(t *testing.T) {
+	ht := NewHashTableOpenOf[string, int](4, nil, nil)
+	for i := 0; i < 20; i++ {
+		ht.Insert(fmt.Sprintf("key-%d", i), i)
+	}
+	for i := 0; i < 20; i++ {
+		val, found := ht.Get(fmt.Sprintf("key-%d", i))
+		assert.True(t, found)
+		assert.Equal(t, i, val)
+	}
+
+	ht.Remove("key-3")
+	_, found := ht.Get("key-3")
+	assert.False(t, found)
+
+	filename := "test_htopen_strings.bin"
+	defer os.Remove(filename)
+	require.NoError(t, ht.Serialize(filename))
+
+	ht2 := NewHashTableOpenOf[string, int](4, nil, nil)
+	require.NoError(t, ht2.Deserialize(filename))
+	val, found := ht2.Get("key-19")
+	assert.True(t, found)
+	assert.Equal(t, 19, val)
+}
+
+func TestHashTableOpenOf_CustomEqual(t *testing.T) {
+	// Case-insensitive keys
+	seeded := StringHasher()
+	hasher := func(key string) uint64 {
+		return seeded(strings.ToLower(key))
+	}
+	equal := func(a, b string) bool {
+		return strings.EqualFold(a, b)
+	}
+	ht := NewHashTableOpenOf[string, int](8, hasher, equal)
+	ht.Insert("Hello", 1)
+	ht.Insert("HELLO", 2)
+
+	val, found := ht.Get("hello")
+	assert.True(t, found)
+	assert.Equal(t, 2, val)
+}
+
 // ==================== AVLTree Tests ====================
 
 func TestNewAVLTree(t *testing.T) {
@@ -1614,6 +1900,3278 @@ func TestAVLTree_RemoveRootWithChildren(t *testing.T) {
 	assert.True(t, tree.Find(30))
 }
 
+func TestAVLTreeOf_PutGetDelete(t *testing.T) {
+	tree := NewAVLTreeOf[string, int]()
+	assert.Equal(t, 0, tree.Len())
+
+	words := []string{"pear", "apple", "fig", "banana", "cherry", "date"}
+	for i, w := range words {
+		tree.Put(w, i)
+	}
+	assert.Equal(t, len(words), tree.Len())
+
+	val, found := tree.Get("fig")
+	assert.True(t, found)
+	assert.Equal(t, 2, val)
+
+	// Put replaces the value of an existing key
+	tree.Put("fig", 42)
+	val, _ = tree.Get("fig")
+	assert.Equal(t, 42, val)
+	assert.Equal(t, len(words), tree.Len())
+
+	// Insert keeps the existing value
+	tree.Insert("fig")
+	val, _ = tree.Get("fig")
+	assert.Equal(t, 42, val)
+
+	assert.True(t, tree.Delete("pear"))
+	assert.False(t, tree.Delete("pear"))
+	_, found = tree.Get("pear")
+	assert.False(t, found)
+	assert.Equal(t, len(words)-1, tree.Len())
+
+	// Deleting a node with two children moves the successor's value too
+	for _, w := range []string{"apple", "banana", "cherry", "date"} {
+		before, _ := tree.Get(w)
+		tree.Delete("fig")
+		after, found := tree.Get(w)
+		assert.True(t, found)
+		assert.Equal(t, before, after)
+	}
+	tree.Print()
+}
+
+func TestAVLTreeOf_SerializeValues(t *testing.T) {
+	tree := NewAVLTreeOf[int, string]()
+	for i := -5; i <= 5; i++ {
+		tree.Put(i, fmt.Sprintf("v%d", i))
+	}
+
+	filename := "test_avl_map.bin"
+	defer os.Remove(filename)
+	require.NoError(t, tree.Serialize(filename))
+
+	tree2 := NewAVLTreeOf[int, string]()
+	tree2.Put(100, "old")
+	require.NoError(t, tree2.Deserialize(filename))
+	assert.Equal(t, 11, tree2.Len())
+	assert.False(t, tree2.Find(100))
+	for i := -5; i <= 5; i++ {
+		val, found := tree2.Get(i)
+		assert.True(t, found)
+		assert.Equal(t, fmt.Sprintf("v%d", i), val)
+	}
+
+	jsonFile := "test_avl_map.json"
+	defer os.Remove(jsonFile)
+	require.NoError(t, tree.SerializeJSON(jsonFile))
+
+	tree3 := NewAVLTreeOf[int, string]()
+	require.NoError(t, tree3.DeserializeJSON(jsonFile))
+	assert.Equal(t, 11, tree3.Len())
+	val, _ := tree3.Get(-1)
+	assert.Equal(t, "v-1", val)
+}
+
+func TestAVLTree_SerializeJSONSetLayout(t *testing.T) {
+	tree := NewAVLTree()
+	tree.Insert(2)
+	tree.Insert(1)
+
+	filename := "test_avl_set_layout.json"
+	defer os.Remove(filename)
+	require.NoError(t, tree.SerializeJSON(filename))
+
+	raw, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"keys": [1, 2]}`, string(raw))
+}
+
+// ==================== Container Tests ====================
+
+type containerCase struct {
+	c     Container[int]
+	want  []int
+	empty func() Container[int]
+}
+
+// containerCases returns each structure filled with 1, 2, 3 (in that call
+// order) next to the Values it is expected to report.
+func containerCases() map[string]containerCase {
+	arr := NewMyArray()
+	sll := NewSinglyLinkedList()
+	dll := NewDoublyLinkedList()
+	stack := NewMyStack()
+	queue := NewMyQueue()
+	htc := NewHashTableChain(8)
+	hto := NewHashTableOpen(8)
+	htk := NewHashTableCuckoo(8)
+	tree := NewAVLTree()
+	deque := NewDeque()
+	dstack := NewMyStackOnDeque()
+	dqueue := NewMyQueueOnDeque()
+	pq := NewPriorityQueue()
+	ipq := NewIndexedPriorityQueue()
+	skip := NewSkipList()
+	rbt := NewRedBlackTree()
+	for _, v := range []int{1, 2, 3} {
+		arr.AddToEnd(v)
+		sll.PushBack(v)
+		dll.PushBack(v)
+		stack.Push(v)
+		queue.Push(v)
+		htc.Insert(v, v)
+		hto.Insert(v, v)
+		htk.Insert(v, v)
+		tree.Insert(v)
+		deque.PushBack(v)
+		dstack.Push(v)
+		dqueue.Push(v)
+		pq.Push(v)
+		ipq.Push(v, -v)
+		skip.Insert(v)
+		rbt.Insert(v)
+	}
+
+	return map[string]containerCase{
+		"MyArray":          {arr, []int{1, 2, 3}, func() Container[int] { return NewMyArray() }},
+		"SinglyLinkedList": {sll, []int{1, 2, 3}, func() Container[int] { return NewSinglyLinkedList() }},
+		"DoublyLinkedList": {dll, []int{1, 2, 3}, func() Container[int] { return NewDoublyLinkedList() }},
+		"MyStack":          {stack, []int{3, 2, 1}, func() Container[int] { return NewMyStack() }},
+		"MyQueue":          {queue, []int{1, 2, 3}, func() Container[int] { return NewMyQueue() }},
+		"HashTableChain":   {htc, []int{1, 2, 3}, func() Container[int] { return NewHashTableChain(8) }},
+		"HashTableOpen":    {hto, []int{1, 2, 3}, func() Container[int] { return NewHashTableOpen(8) }},
+		"HashTableCuckoo":  {htk, []int{1, 2, 3}, func() Container[int] { return NewHashTableCuckoo(8) }},
+		"AVLTree":          {tree, []int{1, 2, 3}, func() Container[int] { return NewAVLTree() }},
+		"Deque":            {deque, []int{1, 2, 3}, func() Container[int] { return NewDeque() }},
+		"MyStackOnDeque":   {dstack, []int{3, 2, 1}, func() Container[int] { return NewMyStackOnDeque() }},
+		"MyQueueOnDeque":   {dqueue, []int{1, 2, 3}, func() Container[int] { return NewMyQueueOnDeque() }},
+		"PriorityQueue":    {pq, []int{1, 2, 3}, func() Container[int] { return NewPriorityQueue() }},
+		"IndexedPQ":        {ipq, []int{1, 2, 3}, func() Container[int] { return NewIndexedPriorityQueue() }},
+		"SkipList":         {skip, []int{1, 2, 3}, func() Container[int] { return NewSkipList() }},
+		"RedBlackTree":     {rbt, []int{1, 2, 3}, func() Container[int] { return NewRedBlackTree() }},
+	}
+}
+
+func TestContainer_Common(t *testing.T) {
+	for name, tc := range containerCases() {
+		t.Run(name, func(t *testing.T) {
+			c := tc.c
+			assert.Equal(t, 3, c.Len())
+			assert.False(t, c.IsEmpty())
+			assert.ElementsMatch(t, tc.want, c.Values())
+			if _, ok := c.(Sequence[int]); ok {
+				assert.Equal(t, tc.want, c.Values())
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, c.SerializeTo(&buf))
+			restored := tc.empty()
+			require.NoError(t, restored.DeserializeFrom(&buf))
+			assert.Equal(t, c.Len(), restored.Len())
+			assert.ElementsMatch(t, c.Values(), restored.Values())
+			if _, ok := c.(Sequence[int]); ok {
+				assert.Equal(t, c.Values(), restored.Values())
+			}
+
+			c.Clear()
+			assert.Equal(t, 0, c.Len())
+			assert.True(t, c.IsEmpty())
+			assert.Empty(t, c.Values())
+		})
+	}
+}
+
+func TestSequence_At(t *testing.T) {
+	for name, tc := range containerCases() {
+		seq, ok := tc.c.(Sequence[int])
+		if !ok {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			for i, want := range tc.want {
+				got, err := seq.At(i)
+				assert.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+			_, err := seq.At(len(tc.want))
+			assert.Error(t, err)
+			_, err = seq.At(-1)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMap_Keys(t *testing.T) {
+	var maps = []Map[int, int]{NewHashTableChain(4), NewHashTableOpen(4), NewHashTableCuckoo(4)}
+	for _, m := range maps {
+		m.Insert(1, 10)
+		m.Insert(5, 50)
+		assert.ElementsMatch(t, []int{1, 5}, m.Keys())
+		assert.Equal(t, len(m.Keys()), len(m.Values()))
+	}
+}
+
+func TestStack_SerializeKeepsOrder(t *testing.T) {
+	stack := NewMyStack()
+	stack.Push(1)
+	stack.Push(2)
+	stack.Push(3)
+
+	filename := "test_stack_order.bin"
+	defer os.Remove(filename)
+	require.NoError(t, stack.Serialize(filename))
+	stack2 := NewMyStack()
+	require.NoError(t, stack2.Deserialize(filename))
+	assert.Equal(t, []int{3, 2, 1}, stack2.Values())
+
+	jsonFile := "test_stack_order.json"
+	defer os.Remove(jsonFile)
+	require.NoError(t, stack.SerializeJSON(jsonFile))
+	stack3 := NewMyStack()
+	require.NoError(t, stack3.DeserializeJSON(jsonFile))
+	assert.Equal(t, []int{3, 2, 1}, stack3.Values())
+}
+
+// ==================== Iterator Tests ====================
+
+func TestMyArray_All(t *testing.T) {
+	arr := NewMyArrayOf[string]()
+	arr.AddToEnd("a")
+	arr.AddToEnd("b")
+	arr.AddToEnd("c")
+
+	got := make([]string, 0)
+	for i, v := range arr.All() {
+		assert.Equal(t, []string{"a", "b", "c"}[i], v)
+		got = append(got, v)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, got)
+
+	// Early break
+	count := 0
+	for range arr.All() {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+
+	// Replacing in place is allowed, structural changes are not
+	for i := range arr.All() {
+		arr.ReplaceAtIndex(i, "x")
+	}
+	assert.PanicsWithValue(t, ErrModifiedDuringIteration, func() {
+		for range arr.All() {
+			arr.AddToEnd("d")
+		}
+	})
+}
+
+func TestLinkedLists_All(t *testing.T) {
+	sll := NewSinglyLinkedList()
+	dll := NewDoublyLinkedList()
+	for _, v := range []int{10, 20, 30} {
+		sll.PushBack(v)
+		dll.PushBack(v)
+	}
+
+	sllPairs := make([][2]int, 0)
+	for i, v := range sll.All() {
+		sllPairs = append(sllPairs, [2]int{i, v})
+	}
+	assert.Equal(t, [][2]int{{0, 10}, {1, 20}, {2, 30}}, sllPairs)
+
+	dllPairs := make([][2]int, 0)
+	for i, v := range dll.All() {
+		dllPairs = append(dllPairs, [2]int{i, v})
+	}
+	assert.Equal(t, sllPairs, dllPairs)
+
+	backward := make([][2]int, 0)
+	for i, v := range dll.Backward() {
+		backward = append(backward, [2]int{i, v})
+		if v == 20 {
+			break
+		}
+	}
+	assert.Equal(t, [][2]int{{2, 30}, {1, 20}}, backward)
+
+	assert.PanicsWithValue(t, ErrModifiedDuringIteration, func() {
+		for range sll.All() {
+			sll.PopFront()
+		}
+	})
+	assert.PanicsWithValue(t, ErrModifiedDuringIteration, func() {
+		for range dll.Backward() {
+			dll.PushBack(1)
+		}
+	})
+}
+
+func TestStackQueue_All(t *testing.T) {
+	stack := NewMyStack()
+	queue := NewMyQueue()
+	for _, v := range []int{1, 2, 3} {
+		stack.Push(v)
+		queue.Push(v)
+	}
+
+	got := make([]int, 0)
+	for v := range stack.All() {
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{3, 2, 1}, got)
+
+	got = got[:0]
+	for v := range queue.All() {
+		got = append(got, v)
+		if len(got) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2}, got)
+
+	assert.PanicsWithValue(t, ErrModifiedDuringIteration, func() {
+		for range stack.All() {
+			stack.Push(4)
+		}
+	})
+	assert.PanicsWithValue(t, ErrModifiedDuringIteration, func() {
+		for range queue.All() {
+			queue.Pop()
+		}
+	})
+}
+
+func TestHashTables_All(t *testing.T) {
+	tables := map[string]Map[int, int]{
+		"chain":  NewHashTableChain(4),
+		"open":   NewHashTableOpen(4),
+		"cuckoo": NewHashTableCuckoo(4),
+	}
+	for name, m := range tables {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				m.Insert(i, i*i)
+			}
+			all := m.(interface{ All() iter.Seq2[int, int] }).All()
+
+			seen := make(map[int]int)
+			for k, v := range all {
+				seen[k] = v
+			}
+			assert.Len(t, seen, 10)
+			for k, v := range seen {
+				assert.Equal(t, k*k, v)
+			}
+
+			count := 0
+			for range all {
+				count++
+				if count == 3 {
+					break
+				}
+			}
+			assert.Equal(t, 3, count)
+
+			assert.PanicsWithValue(t, ErrModifiedDuringIteration, func() {
+				for k := range all {
+					m.Remove(k)
+				}
+			})
+		})
+	}
+}
+
+func TestAVLTree_All(t *testing.T) {
+	tree := NewAVLTreeOf[int, string]()
+	for _, k := range []int{50, 20, 80, 10, 30, 70, 90} {
+		tree.Put(k, fmt.Sprint(k))
+	}
+
+	keys := make([]int, 0)
+	for k, v := range tree.All() {
+		assert.Equal(t, fmt.Sprint(k), v)
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []int{10, 20, 30, 50, 70, 80, 90}, keys)
+
+	keys = keys[:0]
+	for k := range tree.All() {
+		if k > 30 {
+			break
+		}
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []int{10, 20, 30}, keys)
+
+	// Updating a value is not a structural change
+	for k := range tree.All() {
+		tree.Put(k, "updated")
+	}
+
+	assert.PanicsWithValue(t, ErrModifiedDuringIteration, func() {
+		for k := range tree.All() {
+			tree.Remove(k)
+		}
+	})
+}
+
+// ==================== AVLTree Query Tests ====================
+
+func TestAVLTree_MinMax(t *testing.T) {
+	tree := NewAVLTree()
+	_, ok := tree.Min()
+	assert.False(t, ok)
+	_, ok = tree.Max()
+	assert.False(t, ok)
+
+	for _, k := range []int{40, 10, 70, -5, 25} {
+		tree.Insert(k)
+	}
+	minKey, ok := tree.Min()
+	assert.True(t, ok)
+	assert.Equal(t, -5, minKey)
+	maxKey, ok := tree.Max()
+	assert.True(t, ok)
+	assert.Equal(t, 70, maxKey)
+}
+
+func TestAVLTree_FloorCeiling(t *testing.T) {
+	tree := NewAVLTree()
+	for _, k := range []int{10, 20, 30, 40, 50} {
+		tree.Insert(k)
+	}
+
+	tests := []struct {
+		key                int
+		floor, ceiling     int
+		floorOK, ceilingOK bool
+		pred, succ         int
+		predOK, succOK     bool
+	}{
+		{key: 5, ceiling: 10, ceilingOK: true, succ: 10, succOK: true},
+		{key: 10, floor: 10, floorOK: true, ceiling: 10, ceilingOK: true, succ: 20, succOK: true},
+		{key: 25, floor: 20, floorOK: true, ceiling: 30, ceilingOK: true, pred: 20, predOK: true, succ: 30, succOK: true},
+		{key: 50, floor: 50, floorOK: true, ceiling: 50, ceilingOK: true, pred: 40, predOK: true},
+		{key: 99, floor: 50, floorOK: true, pred: 50, predOK: true},
+	}
+	for _, tt := range tests {
+		got, ok := tree.Floor(tt.key)
+		assert.Equal(t, tt.floorOK, ok, "Floor(%d)", tt.key)
+		assert.Equal(t, tt.floor, got, "Floor(%d)", tt.key)
+
+		got, ok = tree.Ceiling(tt.key)
+		assert.Equal(t, tt.ceilingOK, ok, "Ceiling(%d)", tt.key)
+		assert.Equal(t, tt.ceiling, got, "Ceiling(%d)", tt.key)
+
+		got, ok = tree.Predecessor(tt.key)
+		assert.Equal(t, tt.predOK, ok, "Predecessor(%d)", tt.key)
+		assert.Equal(t, tt.pred, got, "Predecessor(%d)", tt.key)
+
+		got, ok = tree.Successor(tt.key)
+		assert.Equal(t, tt.succOK, ok, "Successor(%d)", tt.key)
+		assert.Equal(t, tt.succ, got, "Successor(%d)", tt.key)
+	}
+}
+
+func TestAVLTree_Range(t *testing.T) {
+	tree := NewAVLTreeOf[int, string]()
+	for i := 0; i < 100; i += 5 {
+		tree.Put(i, fmt.Sprint(i))
+	}
+
+	keys := make([]int, 0)
+	for k, v := range tree.Range(12, 40) {
+		assert.Equal(t, fmt.Sprint(k), v)
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []int{15, 20, 25, 30, 35, 40}, keys)
+
+	assert.Equal(t, 6, tree.CountRange(12, 40))
+	assert.Equal(t, 20, tree.CountRange(-100, 1000))
+	assert.Equal(t, 0, tree.CountRange(41, 44))
+	assert.Equal(t, 0, tree.CountRange(50, 10))
+
+	// Early break
+	keys = keys[:0]
+	for k := range tree.Range(0, 99) {
+		keys = append(keys, k)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{0, 5}, keys)
+}
+
+func TestAVLTree_RemoveRange(t *testing.T) {
+	tree := NewAVLTree()
+	for i := 1; i <= 50; i++ {
+		tree.Insert(i)
+	}
+
+	assert.Equal(t, 11, tree.RemoveRange(20, 30))
+	assert.Equal(t, 39, tree.Len())
+	assert.False(t, tree.Find(25))
+	assert.True(t, tree.Find(19))
+	assert.True(t, tree.Find(31))
+
+	next, ok := tree.Ceiling(20)
+	assert.True(t, ok)
+	assert.Equal(t, 31, next)
+
+	assert.Equal(t, 0, tree.RemoveRange(20, 30))
+}
+
+// ==================== AVLTree Order Statistic Tests ====================
+
+// checkAVLNode verifies ordering, balance, height and size bookkeeping and
+// returns the subtree height and size.
+func checkAVLNode[K cmp.Ordered, V any](t *testing.T, n *AVLNode[K, V], lo, hi *K) (int, int) {
+	if n == nil {
+		return 0, 0
+	}
+	if lo != nil {
+		require.Less(t, *lo, n.key)
+	}
+	if hi != nil {
+		require.Greater(t, *hi, n.key)
+	}
+	lh, ls := checkAVLNode(t, n.left, lo, &n.key)
+	rh, rs := checkAVLNode(t, n.right, &n.key, hi)
+	require.LessOrEqual(t, lh-rh, 1, "unbalanced at %v", n.key)
+	require.LessOrEqual(t, rh-lh, 1, "unbalanced at %v", n.key)
+	require.Equal(t, max(lh, rh)+1, n.height, "height at %v", n.key)
+	require.Equal(t, ls+rs+1, n.size, "size at %v", n.key)
+	return n.height, n.size
+}
+
+func TestAVLTree_RankSelect(t *testing.T) {
+	tree := NewAVLTree()
+	rng := rand.New(rand.NewSource(7))
+	present := make(map[int]bool)
+
+	for i := 0; i < 2000; i++ {
+		k := rng.Intn(500)
+		if rng.Intn(3) == 0 {
+			tree.Remove(k)
+			delete(present, k)
+		} else {
+			tree.Insert(k)
+			present[k] = true
+		}
+	}
+	checkAVLNode(t, tree.root, nil, nil)
+	assert.Equal(t, len(present), tree.Len())
+
+	sorted := tree.Values()
+	for i, k := range sorted {
+		assert.Equal(t, i, tree.Rank(k))
+		got, ok := tree.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, k, got)
+	}
+
+	// Rank of absent keys counts everything below them
+	assert.Equal(t, 0, tree.Rank(-1))
+	assert.Equal(t, len(sorted), tree.Rank(1000))
+
+	_, ok := tree.Select(-1)
+	assert.False(t, ok)
+	_, ok = tree.Select(tree.Len())
+	assert.False(t, ok)
+
+	assert.Equal(t, len(sorted), tree.CountRange(-1, 1000))
+}
+
+func TestAVLTree_SizeAfterDeserialize(t *testing.T) {
+	tree := NewAVLTree()
+	for i := 0; i < 31; i++ {
+		tree.Insert(i)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, tree.SerializeTo(&buf))
+	tree2 := NewAVLTree()
+	require.NoError(t, tree2.DeserializeFrom(&buf))
+
+	checkAVLNode(t, tree2.root, nil, nil)
+	assert.Equal(t, 31, tree2.Len())
+	median, _ := tree2.Select(15)
+	assert.Equal(t, 15, median)
+}
+
+// ==================== AVLTree Set Algebra Tests ====================
+
+func randomKeySet(rng *rand.Rand, n, keyRange int) map[int]bool {
+	keys := make(map[int]bool)
+	for i := 0; i < n; i++ {
+		keys[rng.Intn(keyRange)] = true
+	}
+	return keys
+}
+
+func avlTreeFrom(keys map[int]bool) *AVLTree[int, struct{}] {
+	tree := NewAVLTree()
+	for k := range keys {
+		tree.Insert(k)
+	}
+	return tree
+}
+
+func sortedKeys(keys map[int]bool) []int {
+	out := make([]int, 0, len(keys))
+	for k := range keys {
+		out = append(out, k)
+	}
+	sort.Ints(out)
+	return out
+}
+
+func TestAVLTree_SplitJoin(t *testing.T) {
+	tree := NewAVLTree()
+	for i := 0; i < 100; i++ {
+		tree.Insert(i * 2)
+	}
+
+	left, right := tree.Split(51)
+	assert.Equal(t, 0, tree.Len())
+	checkAVLNode(t, left.root, nil, nil)
+	checkAVLNode(t, right.root, nil, nil)
+	assert.Equal(t, 26, left.Len())
+	assert.Equal(t, 74, right.Len())
+	minRight, _ := right.Min()
+	assert.Equal(t, 52, minRight)
+
+	// A present split key goes to the right half
+	l2, r2 := right.Split(60)
+	maxLeft, _ := l2.Max()
+	minRight, _ = r2.Min()
+	assert.Equal(t, 58, maxLeft)
+	assert.Equal(t, 60, minRight)
+
+	require.NoError(t, left.Join(l2))
+	require.NoError(t, left.Join(r2))
+	checkAVLNode(t, left.root, nil, nil)
+	assert.Equal(t, 100, left.Len())
+	assert.Equal(t, 0, r2.Len())
+
+	bad := NewAVLTree()
+	bad.Insert(10)
+	assert.Error(t, left.Join(bad))
+	assert.Equal(t, 1, bad.Len())
+}
+
+func TestAVLTree_JoinUnevenHeights(t *testing.T) {
+	small := NewAVLTree()
+	small.Insert(-1)
+	big := NewAVLTree()
+	for i := 0; i < 1000; i++ {
+		big.Insert(i)
+	}
+	require.NoError(t, small.Join(big))
+	checkAVLNode(t, small.root, nil, nil)
+	assert.Equal(t, 1001, small.Len())
+
+	tail := NewAVLTree()
+	tail.Insert(5000)
+	require.NoError(t, small.Join(tail))
+	checkAVLNode(t, small.root, nil, nil)
+	assert.Equal(t, 1002, small.Len())
+}
+
+func TestAVLTree_SetOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	sizes := [][2]int{{0, 50}, {50, 0}, {10, 500}, {500, 10}, {300, 300}}
+
+	for _, sz := range sizes {
+		ka := randomKeySet(rng, sz[0], 1000)
+		kb := randomKeySet(rng, sz[1], 1000)
+		union := make(map[int]bool)
+		inter := make(map[int]bool)
+		diff := make(map[int]bool)
+		for k := range ka {
+			union[k] = true
+			if kb[k] {
+				inter[k] = true
+			} else {
+				diff[k] = true
+			}
+		}
+		for k := range kb {
+			union[k] = true
+		}
+
+		a, b := avlTreeFrom(ka), avlTreeFrom(kb)
+		a.Union(b)
+		checkAVLNode(t, a.root, nil, nil)
+		assert.Equal(t, sortedKeys(union), a.Values())
+
+		// Union copies what it takes from b instead of sharing nodes
+		nodesOfB := make(map[*AVLNode[int, struct{}]]bool)
+		var collect func(n *AVLNode[int, struct{}], into map[*AVLNode[int, struct{}]]bool)
+		collect = func(n *AVLNode[int, struct{}], into map[*AVLNode[int, struct{}]]bool) {
+			if n != nil {
+				into[n] = true
+				collect(n.left, into)
+				collect(n.right, into)
+			}
+		}
+		collect(b.root, nodesOfB)
+		nodesOfA := make(map[*AVLNode[int, struct{}]]bool)
+		collect(a.root, nodesOfA)
+		for n := range nodesOfA {
+			require.False(t, nodesOfB[n], "key %d shared", n.key)
+		}
+
+		// The argument keeps its keys and stays usable
+		a = avlTreeFrom(ka)
+		a.Intersection(b)
+		checkAVLNode(t, a.root, nil, nil)
+		assert.Equal(t, sortedKeys(inter), a.Values())
+
+		a = avlTreeFrom(ka)
+		a.Difference(b)
+		checkAVLNode(t, a.root, nil, nil)
+		assert.Equal(t, sortedKeys(diff), a.Values())
+
+		checkAVLNode(t, b.root, nil, nil)
+		assert.Equal(t, sortedKeys(kb), b.Values())
+		b.Insert(-1)
+		assert.Equal(t, len(kb)+1, b.Len())
+		assert.NotContains(t, a.Values(), -1)
+	}
+}
+
+func TestAVLTree_UnionKeepsOwnValues(t *testing.T) {
+	a := NewAVLTreeOf[string, int]()
+	b := NewAVLTreeOf[string, int]()
+	a.Put("x", 1)
+	a.Put("y", 2)
+	b.Put("y", 20)
+	b.Put("z", 30)
+
+	a.Union(b)
+	assert.Equal(t, []string{"x", "y", "z"}, a.Values())
+	v, _ := a.Get("y")
+	assert.Equal(t, 2, v)
+	v, _ = a.Get("z")
+	assert.Equal(t, 30, v)
+
+	a.Union(a)
+	assert.Equal(t, 3, a.Len())
+	a.Difference(a)
+	assert.Equal(t, 0, a.Len())
+}
+
+func TestAVLTree_IsSubset(t *testing.T) {
+	a := NewAVLTree()
+	b := NewAVLTree()
+	for i := 0; i < 10; i++ {
+		b.Insert(i)
+	}
+	assert.True(t, a.IsSubset(b))
+	a.Insert(3)
+	a.Insert(7)
+	assert.True(t, a.IsSubset(b))
+	assert.False(t, b.IsSubset(a))
+	a.Insert(42)
+	assert.False(t, a.IsSubset(b))
+	assert.Equal(t, 10, b.Len())
+}
+
+// ==================== PersistentAVLTree Tests ====================
+
+func TestPersistentAVLTree_VersionsAreIndependent(t *testing.T) {
+	v0 := NewPersistentAVLTree()
+	v1 := v0.Insert(10).Insert(20).Insert(30)
+	snap := v1.Snapshot()
+	v2 := v1.Insert(40).Remove(10)
+
+	assert.Equal(t, 0, v0.Len())
+	assert.Equal(t, []int{10, 20, 30}, snap.Values())
+	assert.Equal(t, []int{20, 30, 40}, v2.Values())
+	assert.True(t, snap.Find(10))
+	assert.False(t, v2.Find(10))
+
+	// No-op updates return the same version
+	assert.Same(t, v2, v2.Insert(20))
+	assert.Same(t, v2, v2.Remove(99))
+}
+
+func TestPersistentAVLTree_RandomHistory(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	tree := NewPersistentAVLTree()
+	var versions []*PersistentAVLTree[int, struct{}]
+	var expected [][]int
+	present := make(map[int]bool)
+
+	for i := 0; i < 1500; i++ {
+		k := rng.Intn(300)
+		if rng.Intn(3) == 0 {
+			tree = tree.Remove(k)
+			delete(present, k)
+		} else {
+			tree = tree.Insert(k)
+			present[k] = true
+		}
+		if i%100 == 0 {
+			versions = append(versions, tree.Snapshot())
+			expected = append(expected, sortedKeys(present))
+		}
+	}
+
+	for i, v := range versions {
+		checkAVLNode(t, v.root, nil, nil)
+		assert.Equal(t, expected[i], v.Values())
+	}
+}
+
+func TestPersistentAVLTree_PutSharesUntouchedNodes(t *testing.T) {
+	v1 := NewPersistentAVLTreeOf[string, int]()
+	for i, k := range []string{"d", "b", "f", "a", "c", "e", "g"} {
+		v1 = v1.Put(k, i)
+	}
+	v2 := v1.Put("a", 100)
+
+	old, _ := v1.Get("a")
+	updated, _ := v2.Get("a")
+	assert.Equal(t, 3, old)
+	assert.Equal(t, 100, updated)
+	assert.Same(t, v1.root.right, v2.root.right)
+	assert.NotSame(t, v1.root.left, v2.root.left)
+}
+
+func TestPersistentAVLTree_SerializeAndMutable(t *testing.T) {
+	tree := NewPersistentAVLTree()
+	for i := 0; i < 20; i++ {
+		tree = tree.Insert(i)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, tree.SerializeTo(&buf))
+	loaded, err := NewPersistentAVLTree().DeserializeFrom(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, tree.Values(), loaded.Values())
+
+	m := tree.Mutable()
+	m.Remove(5)
+	assert.True(t, tree.Find(5))
+	assert.Equal(t, 19, m.Len())
+}
+
+// ==================== HashTableChain Resize Tests ====================
+
+func TestHashTableChain_PutReplaces(t *testing.T) {
+	ht := NewHashTableChain(8)
+	ht.Put(1, 100)
+	ht.Put(9, 900)
+	ht.Put(1, 150)
+	ht.Insert(9, 950)
+
+	assert.Equal(t, 2, ht.Len())
+	val, _ := ht.Get(1)
+	assert.Equal(t, 150, val)
+	val, _ = ht.Get(9)
+	assert.Equal(t, 950, val)
+	assert.ElementsMatch(t, []int{1, 9}, ht.Keys())
+}
+
+func TestHashTableChain_GrowAndShrink(t *testing.T) {
+	ht := NewHashTableChain(4)
+	for i := 0; i < 100; i++ {
+		ht.Put(i, i)
+	}
+	assert.Equal(t, 128, ht.Capacity())
+	assert.LessOrEqual(t, ht.LoadFactor(), DefaultChainMaxLoad)
+
+	for i := 0; i < 90; i++ {
+		ht.Remove(i)
+	}
+	assert.Equal(t, 32, ht.Capacity())
+	for i := 90; i < 99; i++ {
+		ht.Remove(i)
+	}
+	assert.Equal(t, 4, ht.Capacity(), "never shrinks below the initial capacity")
+	val, found := ht.Get(99)
+	assert.True(t, found)
+	assert.Equal(t, 99, val)
+}
+
+func TestHashTableChain_SetLoadFactors(t *testing.T) {
+	ht := NewHashTableChain(8)
+	for i := 0; i < 8; i++ {
+		ht.Put(i, i)
+	}
+	assert.Equal(t, 8, ht.Capacity())
+
+	require.NoError(t, ht.SetLoadFactors(0.5, 0))
+	assert.Equal(t, 16, ht.Capacity())
+	for i := 0; i < 8; i++ {
+		ht.Remove(i)
+	}
+	assert.Equal(t, 16, ht.Capacity(), "shrinking disabled")
+
+	assert.Error(t, ht.SetLoadFactors(0, 0))
+	assert.Error(t, ht.SetLoadFactors(1, 0.6))
+}
+
+func TestHashTableChain_Reserve(t *testing.T) {
+	ht := NewHashTableChain(8)
+	ht.Reserve(1000)
+	assert.Equal(t, 1024, ht.Capacity())
+
+	for i := 0; i < 1000; i++ {
+		ht.Put(i, i)
+	}
+	assert.Equal(t, 1024, ht.Capacity())
+	for i := 0; i < 1000; i++ {
+		ht.Remove(i)
+	}
+	assert.Equal(t, 1024, ht.Capacity(), "reserved capacity is kept")
+}
+
+func TestHashTableChain_SerializeKeepsCapacity(t *testing.T) {
+	ht := NewHashTableChain(4)
+	for i := 0; i < 50; i++ {
+		ht.Put(i, i*10)
+	}
+	capacity := ht.Capacity()
+	require.Greater(t, capacity, 4)
+
+	var buf bytes.Buffer
+	require.NoError(t, ht.SerializeTo(&buf))
+	ht2 := NewHashTableChain(4)
+	require.NoError(t, ht2.DeserializeFrom(&buf))
+
+	assert.Equal(t, capacity, ht2.Capacity())
+	assert.Equal(t, 50, ht2.Len())
+	val, _ := ht2.Get(42)
+	assert.Equal(t, 420, val)
+}
+
+// ==================== HashTableOpen Robin Hood Tests ====================
+
+// checkRobinHood verifies every stored distance against the key's home slot
+// and that no entry is further from home than its predecessor allows.
+func checkRobinHood[K, V any](t *testing.T, h *HashTableOpen[K, V]) {
+	for i := 0; i < h.capacity; i++ {
+		e := h.table[i]
+		if !e.isOccupied {
+			continue
+		}
+		require.False(t, e.isDeleted)
+		home := h.hash(e.key)
+		require.Equal(t, (i-home+h.capacity)%h.capacity, e.dist, "slot %d", i)
+		if e.dist > 0 {
+			prev := h.table[(i-1+h.capacity)%h.capacity]
+			require.True(t, prev.isOccupied)
+			require.GreaterOrEqual(t, prev.dist, e.dist-1, "slot %d", i)
+		}
+	}
+}
+
+func TestHashTableOpen_RobinHoodChurn(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	ht := NewHashTableOpen(16)
+	expected := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		k := rng.Intn(400)
+		if rng.Intn(2) == 0 {
+			ht.Remove(k)
+			delete(expected, k)
+		} else {
+			ht.Insert(k, i)
+			expected[k] = i
+		}
+	}
+	checkRobinHood(t, ht)
+	assert.Equal(t, len(expected), ht.Len())
+	for k, v := range expected {
+		got, found := ht.Get(k)
+		require.True(t, found)
+		assert.Equal(t, v, got)
+	}
+	for k := 400; k < 450; k++ {
+		_, found := ht.Get(k)
+		assert.False(t, found)
+	}
+}
+
+func TestHashTableOpen_BackwardShift(t *testing.T) {
+	ht := NewHashTableOpen(16)
+	// 1, 17 and 33 share a home slot, 2 is displaced by them
+	for _, k := range []int{1, 17, 33, 2} {
+		ht.Insert(k, k)
+	}
+	checkRobinHood(t, ht)
+
+	ht.Remove(17)
+	checkRobinHood(t, ht)
+	assert.Equal(t, 1, ht.table[2].dist, "33 shifted back next to its home")
+	assert.False(t, ht.table[4].isOccupied)
+
+	for _, k := range []int{1, 33, 2} {
+		_, found := ht.Get(k)
+		assert.True(t, found)
+	}
+}
+
+func TestHashTableOpen_ProbeStats(t *testing.T) {
+	ht := NewHashTableOpen(8)
+	assert.Equal(t, ProbeStats{}, ht.ProbeStats())
+
+	for i := 0; i < 4; i++ {
+		ht.Insert(i*8, i)
+	}
+	stats := ht.ProbeStats()
+	assert.Equal(t, 3, stats.MaxDistance)
+	assert.InDelta(t, 1.5, stats.AverageDistance, 1e-9)
+
+	for i := 0; i < 4; i++ {
+		ht.Remove(i * 8)
+	}
+	assert.Equal(t, ProbeStats{}, ht.ProbeStats())
+}
+
+// ==================== HashTableOpen Probing Strategy Tests ====================
+
+func TestHashTableOpen_ProbingStrategies(t *testing.T) {
+	strategies := []ProbeStrategy{LinearProbing, QuadraticProbing, DoubleHashing}
+	capacities := []int{8, 16, 7, 13, 10}
+
+	for _, strategy := range strategies {
+		for _, capacity := range capacities {
+			t.Run(fmt.Sprintf("%v/%d", strategy, capacity), func(t *testing.T) {
+				rng := rand.New(rand.NewSource(int64(capacity)))
+				ht := NewHashTableOpen(capacity, HashTableOpenOptions{Probing: strategy})
+				assert.Equal(t, strategy, ht.Probing())
+				expected := make(map[int]int)
+
+				for i := 0; i < 5000; i++ {
+					// Multiples of 16 collide on every power-of-two table
+					k := rng.Intn(200) * 16
+					if rng.Intn(3) == 0 {
+						ht.Remove(k)
+						delete(expected, k)
+					} else {
+						ht.Insert(k, i)
+						expected[k] = i
+					}
+				}
+
+				assert.Equal(t, len(expected), ht.Len())
+				for k, v := range expected {
+					got, found := ht.Get(k)
+					require.True(t, found, "key %d", k)
+					assert.Equal(t, v, got)
+				}
+				_, found := ht.Get(1)
+				assert.False(t, found)
+			})
+		}
+	}
+}
+
+func TestHashTableOpen_QuadraticUsesPrimeCapacities(t *testing.T) {
+	ht := NewHashTableOpen(7, HashTableOpenOptions{Probing: QuadraticProbing})
+	for i := 0; i < 100; i++ {
+		ht.Insert(i*7, i)
+	}
+	assert.Equal(t, nextPrime(ht.Capacity()), ht.Capacity())
+
+	pow2 := NewHashTableOpen(8, HashTableOpenOptions{Probing: QuadraticProbing})
+	for i := 0; i < 100; i++ {
+		pow2.Insert(i*8, i)
+	}
+	assert.True(t, isPowerOfTwo(pow2.Capacity()))
+}
+
+func TestHashTableOpen_TombstonesTriggerRehash(t *testing.T) {
+	ht := NewHashTableOpen(16, HashTableOpenOptions{Probing: DoubleHashing})
+	for round := 0; round < 50; round++ {
+		for i := 0; i < 8; i++ {
+			ht.Insert(round*8+i, i)
+		}
+		for i := 0; i < 8; i++ {
+			ht.Remove(round*8 + i)
+		}
+	}
+	assert.Equal(t, 0, ht.Len())
+	assert.Equal(t, 16, ht.Capacity(), "churn rehashes in place instead of growing")
+	assert.Less(t, ht.deleted, 12)
+}
+
+func TestHashTableOpen_ProbingSerialize(t *testing.T) {
+	ht := NewHashTableOpenOf[string, int](8, nil, nil, HashTableOpenOptions{Probing: DoubleHashing})
+	for i := 0; i < 20; i++ {
+		ht.Insert(fmt.Sprintf("key%d", i), i)
+	}
+	ht.Remove("key3")
+
+	var buf bytes.Buffer
+	require.NoError(t, ht.SerializeTo(&buf))
+	ht2 := NewHashTableOpenOf[string, int](8, nil, nil, HashTableOpenOptions{Probing: QuadraticProbing})
+	require.NoError(t, ht2.DeserializeFrom(&buf))
+
+	assert.Equal(t, 19, ht2.Len())
+	val, found := ht2.Get("key7")
+	assert.True(t, found)
+	assert.Equal(t, 7, val)
+	_, found = ht2.Get("key3")
+	assert.False(t, found)
+}
+
+func TestHashTableOpen_UnknownStrategy(t *testing.T) {
+	assert.Panics(t, func() {
+		NewHashTableOpen(8, HashTableOpenOptions{Probing: ProbeStrategy(42)})
+	})
+}
+
+// ==================== HashTableOpen Group Probing Tests ====================
+
+func TestGroupMatchFunctions(t *testing.T) {
+	w := uint64(0)
+	ctrl := []byte{0x12, ctrlEmpty, 0x34, ctrlDeleted, 0x12, 0x00, ctrlEmpty, 0x7F}
+	for i, b := range ctrl {
+		w |= uint64(b) << (8 * i)
+	}
+
+	m := matchByte(w, 0x12)
+	assert.Equal(t, 0, firstMatch(m))
+	m &= m - 1
+	assert.Equal(t, 4, firstMatch(m))
+
+	assert.Equal(t, 1, firstMatch(matchEmpty(w)))
+	assert.Equal(t, uint64(0x80)<<8|uint64(0x80)<<48, matchEmpty(w))
+	assert.Equal(t, uint64(0x80)<<8|uint64(0x80)<<24|uint64(0x80)<<48, matchEmptyOrDeleted(w))
+	assert.Zero(t, matchByte(w, 0x55))
+}
+
+func TestHashTableOpen_GroupProbing(t *testing.T) {
+	for _, capacity := range []int{1, 8, 10, 64} {
+		t.Run(fmt.Sprint(capacity), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(int64(capacity)))
+			ht := NewHashTableOpen(capacity, HashTableOpenOptions{Probing: GroupProbing})
+			assert.Zero(t, ht.Capacity()%8)
+			expected := make(map[int]int)
+
+			for i := 0; i < 20000; i++ {
+				k := rng.Intn(500) - 250
+				if rng.Intn(3) == 0 {
+					ht.Remove(k)
+					delete(expected, k)
+				} else {
+					ht.Insert(k, i)
+					expected[k] = i
+				}
+			}
+
+			assert.Equal(t, len(expected), ht.Len())
+			assert.ElementsMatch(t, keysOf(expected), ht.Keys())
+			for k, v := range expected {
+				got, found := ht.Get(k)
+				require.True(t, found, "key %d", k)
+				assert.Equal(t, v, got)
+			}
+			for k := 250; k < 300; k++ {
+				_, found := ht.Get(k)
+				assert.False(t, found)
+			}
+			assert.LessOrEqual(t, (ht.size+ht.deleted)*8, ht.capacity*7)
+		})
+	}
+}
+
+func keysOf(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestHashTableOpen_GroupProbingZeroKey(t *testing.T) {
+	// Empty slots hold the zero key, a fingerprint match must not find them
+	ht := NewHashTableOpen(8, HashTableOpenOptions{Probing: GroupProbing})
+	_, found := ht.Get(0)
+	assert.False(t, found)
+	ht.Insert(0, 5)
+	ht.Remove(0)
+	_, found = ht.Get(0)
+	assert.False(t, found)
+	assert.Equal(t, 0, ht.Len())
+}
+
+func TestHashTableOpen_GroupProbingSerialize(t *testing.T) {
+	linear := NewHashTableOpen(10)
+	for i := 0; i < 6; i++ {
+		linear.Insert(i, i*i)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, linear.SerializeTo(&buf))
+	ht := NewHashTableOpen(8, HashTableOpenOptions{Probing: GroupProbing})
+	require.NoError(t, ht.DeserializeFrom(&buf))
+
+	assert.Equal(t, 16, ht.Capacity(), "capacity 10 rounds up to whole groups")
+	assert.Equal(t, 6, ht.Len())
+	val, _ := ht.Get(5)
+	assert.Equal(t, 25, val)
+
+	ht.Clear()
+	assert.Equal(t, 0, ht.Len())
+	_, found := ht.Get(5)
+	assert.False(t, found)
+}
+
+func TestIntHasher(t *testing.T) {
+	assert.Equal(t, uint64(5), IntHasher(-5))
+	assert.Equal(t, uint64(5), IntHasher(5))
+	assert.NotEqual(t, IntHasher(1<<62), IntHasher(1<<62+1))
+}
+
+// ==================== HashTableCuckoo Tests ====================
+
+func TestHashTableCuckoo_Basic(t *testing.T) {
+	ht := NewHashTableCuckoo(0)
+	assert.Equal(t, 16, ht.Capacity())
+
+	ht.Insert(1, 100)
+	ht.Insert(2, 200)
+	ht.Insert(1, 150)
+	assert.Equal(t, 2, ht.Len())
+
+	val, found := ht.Get(1)
+	assert.True(t, found)
+	assert.Equal(t, 150, val)
+	_, found = ht.Get(3)
+	assert.False(t, found)
+
+	ht.Remove(1)
+	ht.Remove(42)
+	_, found = ht.Get(1)
+	assert.False(t, found)
+	assert.Equal(t, 1, ht.Len())
+}
+
+func TestHashTableCuckoo_ManyKeys(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	ht := NewHashTableCuckoo(4)
+	expected := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		k := rng.Intn(3000) - 1500
+		if rng.Intn(4) == 0 {
+			ht.Remove(k)
+			delete(expected, k)
+		} else {
+			ht.Insert(k, i)
+			expected[k] = i
+		}
+	}
+
+	assert.Equal(t, len(expected), ht.Len())
+	assert.LessOrEqual(t, float64(ht.Len()), float64(ht.Capacity())*cuckooMaxLoad)
+	assert.LessOrEqual(t, len(ht.stash), cuckooStashSize)
+	for k, v := range expected {
+		got, found := ht.Get(k)
+		require.True(t, found, "key %d", k)
+		assert.Equal(t, v, got)
+	}
+
+	// Every key sits in one of its two candidate slots
+	for tbl := 0; tbl < 2; tbl++ {
+		for i, slot := range ht.tables[tbl] {
+			if slot.isOccupied {
+				assert.Equal(t, i, ht.index(tbl, slot.key))
+			}
+		}
+	}
+}
+
+func TestHashTableCuckoo_CycleForcesRehash(t *testing.T) {
+	// Keys come in pairs with the same hash, and each pair fills both of
+	// its slots, so any overlap between pairs makes the eviction chain
+	// cycle and forces a rehash
+	hasher := func(k int) uint64 { return uint64(k / 2) }
+	ht := NewHashTableCuckooOf[int, int](2, hasher, nil)
+	for k := 0; k < 40; k++ {
+		ht.Insert(k, k)
+	}
+	assert.Equal(t, 40, ht.Len())
+	for k := 0; k < 40; k++ {
+		val, found := ht.Get(k)
+		assert.True(t, found)
+		assert.Equal(t, k, val)
+	}
+}
+
+func TestHashTableCuckoo_DegenerateHasher(t *testing.T) {
+	// A constant hasher gives every key the same two slots, so all but two
+	// keys end up in the stash and the table only grows with the load
+	ht := NewHashTableCuckooOf[int, int](4, func(int) uint64 { return 7 }, nil)
+	for k := 0; k < 100; k++ {
+		ht.Insert(k, k)
+	}
+	assert.Equal(t, 100, ht.Len())
+	assert.LessOrEqual(t, ht.Capacity(), 512)
+	for k := 0; k < 100; k++ {
+		val, found := ht.Get(k)
+		require.True(t, found, "key %d", k)
+		assert.Equal(t, k, val)
+	}
+
+	ht.Insert(50, 500)
+	val, _ := ht.Get(50)
+	assert.Equal(t, 500, val)
+	for k := 0; k < 100; k += 2 {
+		ht.Remove(k)
+	}
+	assert.Equal(t, 50, ht.Len())
+	assert.Len(t, ht.Keys(), 50)
+	_, found := ht.Get(98)
+	assert.False(t, found)
+	_, found = ht.Get(99)
+	assert.True(t, found)
+	ht.Print()
+}
+
+func TestHashTableCuckoo_IntHasherSignCollisions(t *testing.T) {
+	// IntHasher maps k and -k to the same hash
+	ht := NewHashTableCuckooOf[int, int](4, IntHasher, nil)
+	for k := -200; k <= 200; k++ {
+		ht.Insert(k, k)
+	}
+	assert.Equal(t, 401, ht.Len())
+	assert.LessOrEqual(t, len(ht.stash), cuckooStashSize)
+	for k := -200; k <= 200; k++ {
+		val, found := ht.Get(k)
+		require.True(t, found, "key %d", k)
+		assert.Equal(t, k, val)
+	}
+}
+
+func TestHashTableCuckoo_Serialize(t *testing.T) {
+	ht := NewHashTableCuckooOf[string, int](4, nil, nil)
+	for i := 0; i < 50; i++ {
+		ht.Insert(fmt.Sprintf("k%d", i), i)
+	}
+
+	filename := "test_cuckoo.bin"
+	defer os.Remove(filename)
+	require.NoError(t, ht.Serialize(filename))
+	ht2 := NewHashTableCuckooOf[string, int](4, nil, nil)
+	require.NoError(t, ht2.Deserialize(filename))
+	assert.Equal(t, 50, ht2.Len())
+	val, _ := ht2.Get("k42")
+	assert.Equal(t, 42, val)
+
+	jsonFile := "test_cuckoo.json"
+	defer os.Remove(jsonFile)
+	require.NoError(t, ht.SerializeJSON(jsonFile))
+	ht3 := NewHashTableCuckooOf[string, int](4, nil, nil)
+	require.NoError(t, ht3.DeserializeJSON(jsonFile))
+	assert.ElementsMatch(t, ht.Keys(), ht3.Keys())
+
+	assert.Error(t, ht.Deserialize("/nonexistent/file.bin"))
+	assert.Error(t, ht.SerializeJSON("/nonexistent/dir/file.json"))
+}
+
+func TestHashTableCuckoo_Print(t *testing.T) {
+	ht := NewHashTableCuckoo(4)
+	ht.Insert(1, 10)
+	ht.Print()
+}
+
+// ==================== ConcurrentMap Tests ====================
+
+func TestConcurrentMap_Basic(t *testing.T) {
+	m := NewConcurrentMap(4)
+	m.Store(1, 10)
+	m.Store(2, 20)
+	m.Store(1, 11)
+
+	val, ok := m.Load(1)
+	assert.True(t, ok)
+	assert.Equal(t, 11, val)
+	assert.Equal(t, 2, m.Len())
+
+	actual, loaded := m.LoadOrStore(2, 99)
+	assert.True(t, loaded)
+	assert.Equal(t, 20, actual)
+	actual, loaded = m.LoadOrStore(3, 30)
+	assert.False(t, loaded)
+	assert.Equal(t, 30, actual)
+
+	m.Delete(2)
+	_, ok = m.Load(2)
+	assert.False(t, ok)
+
+	// Compute can insert, update and delete
+	m.Compute(4, func(old int, loaded bool) (int, bool) {
+		assert.False(t, loaded)
+		return 40, true
+	})
+	m.Compute(4, func(old int, loaded bool) (int, bool) { return old + 1, true })
+	val, _ = m.Load(4)
+	assert.Equal(t, 41, val)
+	m.Compute(4, func(old int, loaded bool) (int, bool) { return 0, false })
+	_, ok = m.Load(4)
+	assert.False(t, ok)
+
+	seen := make(map[int]int)
+	m.Range(func(k, v int) bool {
+		seen[k] = v
+		return true
+	})
+	assert.Equal(t, map[int]int{1: 11, 3: 30}, seen)
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestConcurrentMap_ParallelCompute(t *testing.T) {
+	m := NewConcurrentMap(8)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				m.Compute(i%50, func(old int, _ bool) (int, bool) { return old + 1, true })
+				m.Load(i % 50)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, m.Len())
+	m.Range(func(k, v int) bool {
+		assert.Equal(t, 160, v, "key %d", k)
+		return true
+	})
+}
+
+func TestConcurrentMap_RangeCanModify(t *testing.T) {
+	m := NewConcurrentMap(2)
+	for i := 0; i < 10; i++ {
+		m.Store(i, i)
+	}
+	count := 0
+	m.Range(func(k, v int) bool {
+		m.Delete(k)
+		count++
+		return count < 5
+	})
+	assert.Equal(t, 5, count)
+	assert.Equal(t, 5, m.Len())
+}
+
+func TestConcurrentMap_SnapshotSerialize(t *testing.T) {
+	m := NewConcurrentMapOf[string, int](4, nil, nil)
+	for i := 0; i < 100; i++ {
+		m.Store(fmt.Sprintf("k%d", i), i)
+	}
+
+	// A writer keeps updating existing keys while snapshots are taken
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			m.Store(fmt.Sprintf("k%d", i%100), i)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		var buf bytes.Buffer
+		require.NoError(t, m.SerializeTo(&buf))
+		m2 := NewConcurrentMapOf[string, int](2, nil, nil)
+		require.NoError(t, m2.DeserializeFrom(&buf))
+		assert.Equal(t, 100, m2.Len())
+	}
+	close(stop)
+	wg.Wait()
+	for i := 0; i < 100; i++ {
+		m.Store(fmt.Sprintf("k%d", i), i)
+	}
+
+	filename := "test_concurrent_map.json"
+	defer os.Remove(filename)
+	require.NoError(t, m.SerializeJSON(filename))
+	m3 := NewConcurrentMapOf[string, int](3, nil, nil)
+	require.NoError(t, m3.DeserializeJSON(filename))
+	val, ok := m3.Load("k42")
+	assert.True(t, ok)
+	assert.Equal(t, 42, val)
+
+	binFile := "test_concurrent_map.bin"
+	defer os.Remove(binFile)
+	require.NoError(t, m.Serialize(binFile))
+	require.NoError(t, m3.Deserialize(binFile))
+	assert.Equal(t, 100, m3.Len())
+	assert.Error(t, m3.Deserialize("/nonexistent/file.bin"))
+}
+
+// ==================== Incremental Rehash Tests ====================
+
+func TestHashTableOpen_IncrementalRehash(t *testing.T) {
+	for _, strategy := range []ProbeStrategy{LinearProbing, QuadraticProbing, DoubleHashing, GroupProbing} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			h := NewHashTableOpen(8, HashTableOpenOptions{Probing: strategy, IncrementalRehash: true})
+			want := make(map[int]int)
+			rng := rand.New(rand.NewSource(int64(strategy) + 1))
+
+			sawRehash := false
+			for i := 0; i < 5000; i++ {
+				key := rng.Intn(1500)
+				if rng.Intn(3) == 0 {
+					h.Remove(key)
+					delete(want, key)
+				} else {
+					h.Insert(key, i)
+					want[key] = i
+				}
+				sawRehash = sawRehash || h.Rehashing()
+				require.Equal(t, len(want), h.Len())
+				if i%97 == 0 {
+					for k, v := range want {
+						got, ok := h.Get(k)
+						require.True(t, ok, "key %d", k)
+						require.Equal(t, v, got)
+					}
+				}
+			}
+			assert.True(t, sawRehash)
+
+			got := make(map[int]int)
+			for k, v := range h.All() {
+				got[k] = v
+			}
+			assert.Equal(t, want, got)
+			assert.ElementsMatch(t, keysOf(want), h.Keys())
+
+			// Serialization completes the rehash
+			var buf bytes.Buffer
+			require.NoError(t, h.SerializeTo(&buf))
+			assert.False(t, h.Rehashing())
+			h2 := NewHashTableOpen(8, HashTableOpenOptions{Probing: strategy})
+			require.NoError(t, h2.DeserializeFrom(&buf))
+			assert.Equal(t, len(want), h2.Len())
+		})
+	}
+}
+
+func TestHashTableOpen_IncrementalRehashSpreadsWork(t *testing.T) {
+	h := NewHashTableOpen(64, HashTableOpenOptions{IncrementalRehash: true})
+	for i := 0; i < 45; i++ {
+		h.Insert(i, i)
+	}
+	require.False(t, h.Rehashing())
+
+	// The insert that crosses the load factor only swaps tables
+	h.Insert(45, 45)
+	assert.True(t, h.Rehashing())
+	assert.Equal(t, 128, h.Capacity())
+	assert.Equal(t, 46, h.Len())
+	for i := 0; i <= 45; i++ {
+		val, ok := h.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, i, val)
+	}
+
+	// Updates and removals reach keys still in the old table
+	h.Insert(44, 440)
+	val, _ := h.Get(44)
+	assert.Equal(t, 440, val)
+	h.Remove(43)
+	_, ok := h.Get(43)
+	assert.False(t, ok)
+	assert.Equal(t, 45, h.Len())
+
+	// Only writes that add or remove a key migrate
+	h.Get(0)
+	h.Remove(-1)
+	h.Insert(0, 0)
+	assert.True(t, h.Rehashing())
+	for i := 0; h.Rehashing(); i++ {
+		require.Less(t, i, 64/rehashSlots+1)
+		h.Insert(-1, 0)
+		h.Remove(-1)
+	}
+	assert.Equal(t, 45, h.Len())
+	assert.Equal(t, 45, len(h.Values()))
+}
+
+func TestHashTableOpen_UpdateDuringIncrementalRehash(t *testing.T) {
+	for _, strategy := range []ProbeStrategy{LinearProbing, QuadraticProbing, DoubleHashing, GroupProbing} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			h := NewHashTableOpen(8, HashTableOpenOptions{Probing: strategy, IncrementalRehash: true})
+			for i := 0; !h.Rehashing(); i++ {
+				h.Insert(i, i)
+			}
+			require.NotNil(t, h.old)
+
+			// Updating values in place is allowed while iterating
+			assert.NotPanics(t, func() {
+				for k, v := range h.All() {
+					h.Insert(k, v+1000)
+				}
+			})
+			assert.True(t, h.Rehashing())
+			for k, v := range h.All() {
+				assert.Equal(t, k+1000, v)
+			}
+		})
+	}
+}
+
+func TestHashTableChain_IncrementalRehash(t *testing.T) {
+	h := NewHashTableChain(4)
+	h.SetIncrementalRehash(true)
+	want := make(map[int]int)
+	rng := rand.New(rand.NewSource(5))
+
+	sawRehash := false
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(800) - 400
+		if i > 2500 && rng.Intn(4) > 0 {
+			h.Remove(key)
+			delete(want, key)
+		} else {
+			h.Put(key, i)
+			want[key] = i
+		}
+		sawRehash = sawRehash || h.Rehashing()
+		require.Equal(t, len(want), h.Len())
+		if i%97 == 0 {
+			for k, v := range want {
+				got, ok := h.Get(k)
+				require.True(t, ok, "key %d", k)
+				require.Equal(t, v, got)
+			}
+		}
+	}
+	assert.True(t, sawRehash)
+	assert.ElementsMatch(t, keysOf(want), h.Keys())
+
+	h.SetIncrementalRehash(false)
+	assert.False(t, h.Rehashing())
+	got := make(map[int]int)
+	for k, v := range h.All() {
+		got[k] = v
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestHashTableChain_UpdateDuringIncrementalRehash(t *testing.T) {
+	h := NewHashTableChain(16)
+	h.SetIncrementalRehash(true)
+	for i := 0; i < 17; i++ {
+		h.Put(i, i)
+	}
+	require.NotNil(t, h.oldTable)
+
+	// Updating values in place is allowed while iterating
+	assert.NotPanics(t, func() {
+		for k, v := range h.All() {
+			h.Put(k, v+1000)
+		}
+	})
+	assert.True(t, h.Rehashing())
+	for k, v := range h.All() {
+		assert.Equal(t, k+1000, v)
+	}
+}
+
+func TestHashTableChain_IncrementalRehashSpreadsWork(t *testing.T) {
+	h := NewHashTableChain(16)
+	h.SetIncrementalRehash(true)
+	for i := 0; i < 17; i++ {
+		h.Put(i, i)
+	}
+	assert.True(t, h.Rehashing())
+	assert.Equal(t, 32, h.Capacity())
+
+	// A read-only lookup finds keys in either table without migrating
+	for i := 0; i < 17; i++ {
+		val, ok := h.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, i, val)
+	}
+	assert.True(t, h.Rehashing())
+
+	// Neither updates nor removing a missing key migrate
+	h.Put(5, 50)
+	h.Remove(-1)
+	assert.True(t, h.Rehashing())
+	h.Remove(3)
+	for i := 0; h.Rehashing(); i++ {
+		require.Less(t, i, 16/rehashBuckets+1)
+		h.Put(-1, 0)
+		h.Remove(-1)
+	}
+	assert.Equal(t, 16, h.Len())
+	val, _ := h.Get(5)
+	assert.Equal(t, 50, val)
+
+	var buf bytes.Buffer
+	require.NoError(t, h.SerializeTo(&buf))
+	h2 := NewHashTableChain(4)
+	require.NoError(t, h2.DeserializeFrom(&buf))
+	assert.Equal(t, 16, h2.Len())
+}
+
+// ==================== BlockingQueue Tests ====================
+
+func TestBlockingQueue_PutTake(t *testing.T) {
+	q := NewBlockingQueue(2)
+	ctx := context.Background()
+	require.NoError(t, q.Put(ctx, 1))
+	require.NoError(t, q.Put(ctx, 2))
+	assert.Equal(t, 2, q.Len())
+	assert.Equal(t, 2, q.Cap())
+
+	// A full queue blocks Put until a Take makes room
+	done := make(chan error)
+	go func() { done <- q.Put(ctx, 3) }()
+	select {
+	case <-done:
+		t.Fatal("Put on a full queue returned")
+	case <-time.After(20 * time.Millisecond):
+	}
+	val, err := q.Take(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	require.NoError(t, <-done)
+
+	assert.Equal(t, []int{2, 3}, q.Drain())
+	assert.True(t, q.IsEmpty())
+	assert.Empty(t, q.Drain())
+}
+
+func TestBlockingQueue_Cancellation(t *testing.T) {
+	q := NewBlockingQueue(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := q.Take(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, q.Put(context.Background(), 1))
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	assert.ErrorIs(t, q.Put(ctx, 2), context.Canceled)
+	assert.Equal(t, 1, q.Len())
+
+	// Offer and Poll with and without a timeout
+	assert.False(t, q.Offer(2, 0))
+	assert.False(t, q.Offer(2, 10*time.Millisecond))
+	val, ok := q.Poll(0)
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	_, ok = q.Poll(10 * time.Millisecond)
+	assert.False(t, ok)
+	assert.True(t, q.Offer(5, 0))
+}
+
+func TestBlockingQueue_Close(t *testing.T) {
+	q := NewBlockingQueue(0)
+	ctx := context.Background()
+	require.NoError(t, q.Put(ctx, 1))
+
+	// Close wakes a blocked Take on an empty queue
+	empty := NewBlockingQueue(1)
+	done := make(chan error)
+	go func() {
+		_, err := empty.Take(ctx)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	empty.Close()
+	assert.ErrorIs(t, <-done, ErrQueueClosed)
+
+	q.Close()
+	q.Close()
+	assert.True(t, q.IsClosed())
+	assert.ErrorIs(t, q.Put(ctx, 2), ErrQueueClosed)
+	assert.False(t, q.Offer(2, 0))
+
+	// Items queued before Close are still handed out
+	val, err := q.Take(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	_, err = q.Take(ctx)
+	assert.ErrorIs(t, err, ErrQueueClosed)
+}
+
+func TestBlockingQueue_ProducersConsumers(t *testing.T) {
+	q := NewBlockingQueue(4)
+	ctx := context.Background()
+
+	var producers sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		producers.Add(1)
+		go func(p int) {
+			defer producers.Done()
+			for i := 1; i <= 250; i++ {
+				assert.NoError(t, q.Put(ctx, p*1000+i))
+			}
+		}(p)
+	}
+
+	results := make(chan []int)
+	for c := 0; c < 3; c++ {
+		go func() {
+			var got []int
+			for {
+				val, err := q.Take(ctx)
+				if err != nil {
+					results <- got
+					return
+				}
+				got = append(got, val)
+			}
+		}()
+	}
+
+	producers.Wait()
+	q.Close()
+	var all []int
+	for c := 0; c < 3; c++ {
+		got := <-results
+		// Each producer's items reach a consumer in the order they were put
+		last := make(map[int]int)
+		for _, v := range got {
+			assert.Greater(t, v, last[v/1000])
+			last[v/1000] = v
+		}
+		all = append(all, got...)
+	}
+	assert.Len(t, all, 1000)
+	sort.Ints(all)
+	for i := 1; i < len(all); i++ {
+		assert.NotEqual(t, all[i-1], all[i])
+	}
+}
+
+// ==================== LockFreeQueue Tests ====================
+
+func TestLockFreeQueue_Basic(t *testing.T) {
+	q := NewLockFreeQueue()
+	assert.True(t, q.IsEmpty())
+	_, err := q.Peek()
+	assert.Error(t, err)
+	_, ok := q.TryPop()
+	assert.False(t, ok)
+	q.Pop()
+
+	for i := 1; i <= 3; i++ {
+		q.Push(i)
+	}
+	assert.Equal(t, 3, q.Len())
+	assert.Equal(t, []int{1, 2, 3}, q.Values())
+	val, err := q.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+
+	q.Pop()
+	val, ok = q.TryPop()
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+	val, ok = q.TryPop()
+	assert.True(t, ok)
+	assert.Equal(t, 3, val)
+	assert.True(t, q.IsEmpty())
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestLockFreeQueue_Stress(t *testing.T) {
+	const producers, consumers, perProducer = 8, 8, 5000
+	q := NewLockFreeQueue()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Push(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	var remaining sync.WaitGroup
+	remaining.Add(producers * perProducer)
+	results := make([][]int, consumers)
+	done := make(chan struct{})
+	for c := 0; c < consumers; c++ {
+		go func(c int) {
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if val, ok := q.TryPop(); ok {
+					results[c] = append(results[c], val)
+					remaining.Done()
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	remaining.Wait()
+	close(done)
+
+	seen := make(map[int]bool)
+	for _, got := range results {
+		// Values from one producer reach each consumer in push order
+		last := make(map[int]int)
+		for _, v := range got {
+			p := v / perProducer
+			if prev, ok := last[p]; ok {
+				assert.Greater(t, v, prev)
+			}
+			last[p] = v
+			assert.False(t, seen[v], "value %d popped twice", v)
+			seen[v] = true
+		}
+	}
+	assert.Len(t, seen, producers*perProducer)
+	assert.True(t, q.IsEmpty())
+}
+
+// ==================== LockFreeStack Tests ====================
+
+func TestLockFreeStack_Basic(t *testing.T) {
+	s := NewLockFreeStack()
+	assert.True(t, s.IsEmpty())
+	_, err := s.Peek()
+	assert.Error(t, err)
+	_, ok := s.TryPop()
+	assert.False(t, ok)
+	s.Pop()
+
+	for i := 1; i <= 3; i++ {
+		s.Push(i)
+	}
+	assert.Equal(t, 3, s.Len())
+	assert.Equal(t, []int{3, 2, 1}, s.Values())
+	val, err := s.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+
+	s.Pop()
+	val, ok = s.TryPop()
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+	val, ok = s.TryPop()
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestLockFreeStack_Stress(t *testing.T) {
+	const workers, perWorker = 8, 5000
+	s := NewLockFreeStack()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	popped := make(map[int]int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			var got []int
+			for i := 0; i < perWorker; i++ {
+				s.Push(w*perWorker + i)
+				if i%2 == 1 {
+					for j := 0; j < 2; j++ {
+						if val, ok := s.TryPop(); ok {
+							got = append(got, val)
+						}
+					}
+				}
+			}
+			mu.Lock()
+			for _, v := range got {
+				popped[v]++
+			}
+			mu.Unlock()
+		}(w)
+	}
+	wg.Wait()
+
+	for _, v := range s.Values() {
+		popped[v]++
+	}
+	assert.Len(t, popped, workers*perWorker)
+	for v, n := range popped {
+		require.Equal(t, 1, n, "value %d seen %d times", v, n)
+	}
+}
+
+// A shared free-list: every token is owned by at most one worker at a time
+// and none is lost or duplicated.
+func TestLockFreeStack_FreeList(t *testing.T) {
+	const tokens, workers = 16, 8
+	s := NewLockFreeStack()
+	for i := 0; i < tokens; i++ {
+		s.Push(i)
+	}
+
+	var owned [tokens]atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				tok, ok := s.TryPop()
+				if !ok {
+					continue
+				}
+				assert.True(t, owned[tok].CompareAndSwap(false, true), "token %d handed out twice", tok)
+				owned[tok].Store(false)
+				s.Push(tok)
+			}
+		}()
+	}
+	wg.Wait()
+
+	values := s.Values()
+	sort.Ints(values)
+	expected := make([]int, tokens)
+	for i := range expected {
+		expected[i] = i
+	}
+	assert.Equal(t, expected, values)
+	assert.Equal(t, tokens, s.Len())
+}
+
+// ==================== Deque Tests ====================
+
+func TestDeque_Basic(t *testing.T) {
+	d := NewDeque()
+	_, err := d.PopFront()
+	assert.Error(t, err)
+	_, err = d.PopBack()
+	assert.Error(t, err)
+	_, err = d.Front()
+	assert.Error(t, err)
+	_, err = d.Back()
+	assert.Error(t, err)
+
+	d.PushBack(2)
+	d.PushFront(1)
+	d.PushBack(3)
+	assert.Equal(t, []int{1, 2, 3}, d.Values())
+	front, _ := d.Front()
+	back, _ := d.Back()
+	assert.Equal(t, 1, front)
+	assert.Equal(t, 3, back)
+	val, err := d.At(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, val)
+	_, err = d.At(3)
+	assert.Error(t, err)
+
+	var backward []int
+	for v := range d.Backward() {
+		backward = append(backward, v)
+	}
+	assert.Equal(t, []int{3, 2, 1}, backward)
+
+	val, _ = d.PopFront()
+	assert.Equal(t, 1, val)
+	val, _ = d.PopBack()
+	assert.Equal(t, 3, val)
+	assert.Equal(t, 1, d.Len())
+}
+
+func TestDeque_RandomOps(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	d := NewDeque()
+	var model []int
+
+	for i := 0; i < 20000; i++ {
+		// Bias towards growing for the first half and shrinking after
+		grow := rng.Intn(10) < 6
+		if i > 10000 {
+			grow = !grow
+		}
+		switch {
+		case grow && rng.Intn(2) == 0:
+			d.PushFront(i)
+			model = append([]int{i}, model...)
+		case grow:
+			d.PushBack(i)
+			model = append(model, i)
+		case rng.Intn(2) == 0:
+			val, err := d.PopFront()
+			if len(model) == 0 {
+				require.Error(t, err)
+				continue
+			}
+			require.Equal(t, model[0], val)
+			model = model[1:]
+		default:
+			val, err := d.PopBack()
+			if len(model) == 0 {
+				require.Error(t, err)
+				continue
+			}
+			require.Equal(t, model[len(model)-1], val)
+			model = model[:len(model)-1]
+		}
+
+		require.Equal(t, len(model), d.Len())
+		if len(model) > 0 {
+			idx := rng.Intn(len(model))
+			val, err := d.At(idx)
+			require.NoError(t, err)
+			require.Equal(t, model[idx], val)
+		}
+		require.LessOrEqual(t, len(d.buf), max(dequeMinCapacity, 4*len(model)))
+	}
+	assert.Equal(t, append([]int{}, model...), d.Values())
+}
+
+func TestDeque_Serialize(t *testing.T) {
+	d := NewDequeOf[string]()
+	for _, s := range []string{"b", "c", "d"} {
+		d.PushBack(s)
+	}
+	d.PushFront("a")
+
+	filename := "test_deque.bin"
+	defer os.Remove(filename)
+	require.NoError(t, d.Serialize(filename))
+	d2 := NewDequeOf[string]()
+	require.NoError(t, d2.Deserialize(filename))
+	assert.Equal(t, d.Values(), d2.Values())
+
+	jsonFile := "test_deque.json"
+	defer os.Remove(jsonFile)
+	require.NoError(t, d.SerializeJSON(jsonFile))
+	d3 := NewDequeOf[string]()
+	require.NoError(t, d3.DeserializeJSON(jsonFile))
+	assert.Equal(t, d.Values(), d3.Values())
+
+	assert.Error(t, d.Serialize("/nonexistent/dir/file.bin"))
+	assert.Error(t, d.Deserialize("/nonexistent/file.bin"))
+	assert.Error(t, d.SerializeJSON("/nonexistent/dir/file.json"))
+	assert.Error(t, d.DeserializeJSON("/nonexistent/file.json"))
+}
+
+// The deque-backed stack and queue must write the same files as the
+// node-based ones and read theirs.
+func TestDequeBackends_FileCompatibility(t *testing.T) {
+	stack, dstack := NewMyStack(), NewMyStackOnDeque()
+	queue, dqueue := NewMyQueue(), NewMyQueueOnDeque()
+	for i := 0; i < 20; i++ {
+		stack.Push(i * 3)
+		dstack.Push(i * 3)
+		queue.Push(i * 5)
+		dqueue.Push(i * 5)
+	}
+	for i := 0; i < 7; i++ {
+		stack.Pop()
+		dstack.Pop()
+		queue.Pop()
+		dqueue.Pop()
+	}
+
+	for name, pair := range map[string][2]interface {
+		SerializeTo(w io.Writer) error
+		SerializeJSON(filename string) error
+	}{
+		"stack": {stack, dstack},
+		"queue": {queue, dqueue},
+	} {
+		var a, b bytes.Buffer
+		require.NoError(t, pair[0].SerializeTo(&a))
+		require.NoError(t, pair[1].SerializeTo(&b))
+		assert.Equal(t, a.Bytes(), b.Bytes(), name)
+
+		fileA, fileB := "test_compat_a.json", "test_compat_b.json"
+		require.NoError(t, pair[0].SerializeJSON(fileA))
+		require.NoError(t, pair[1].SerializeJSON(fileB))
+		jsonA, _ := os.ReadFile(fileA)
+		jsonB, _ := os.ReadFile(fileB)
+		os.Remove(fileA)
+		os.Remove(fileB)
+		assert.Equal(t, jsonA, jsonB, name)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, stack.SerializeTo(&buf))
+	restored := NewMyStackOnDeque()
+	require.NoError(t, restored.DeserializeFrom(&buf))
+	assert.Equal(t, stack.Values(), restored.Values())
+	top, _ := restored.Peek()
+	assert.Equal(t, 36, top)
+	val, _ := restored.At(1)
+	assert.Equal(t, 33, val)
+
+	buf.Reset()
+	require.NoError(t, queue.SerializeTo(&buf))
+	restoredQueue := NewMyQueueOnDeque()
+	require.NoError(t, restoredQueue.DeserializeFrom(&buf))
+	assert.Equal(t, queue.Values(), restoredQueue.Values())
+	front, _ := restoredQueue.Peek()
+	assert.Equal(t, 35, front)
+
+	restored.Clear()
+	_, err := restored.Peek()
+	assert.Error(t, err)
+	restoredQueue.Clear()
+	_, err = restoredQueue.Peek()
+	assert.Error(t, err)
+}
+
+// ==================== PriorityQueue Tests ====================
+
+func TestPriorityQueue_MinMax(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	values := rng.Perm(500)
+
+	minPQ := NewPriorityQueue()
+	maxPQ := NewMaxPriorityQueue()
+	for _, v := range values {
+		minPQ.Push(v)
+		maxPQ.Push(v)
+	}
+	top, err := minPQ.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, 0, top)
+	top, _ = maxPQ.Peek()
+	assert.Equal(t, 499, top)
+
+	for i := 0; i < 500; i++ {
+		lo, err := minPQ.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, i, lo)
+		hi, _ := maxPQ.Pop()
+		assert.Equal(t, 499-i, hi)
+	}
+	_, err = minPQ.Pop()
+	assert.Error(t, err)
+	_, err = minPQ.Peek()
+	assert.Error(t, err)
+}
+
+func TestPriorityQueue_HeapifyAndMerge(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	byPriority := func(a, b task) bool { return a.priority < b.priority }
+	values := []task{{"write", 3}, {"read", 1}, {"test", 4}, {"ship", 5}}
+	pq := NewPriorityQueueFrom(values, byPriority)
+	values[0].priority = 100 // the queue keeps its own copy
+	other := NewPriorityQueueFrom([]task{{"plan", 0}, {"review", 2}}, byPriority)
+
+	pq.Merge(other)
+	assert.True(t, other.IsEmpty())
+	pq.Merge(pq)
+	assert.Equal(t, 6, pq.Len())
+
+	var order []string
+	for !pq.IsEmpty() {
+		next, _ := pq.Pop()
+		order = append(order, next.name)
+	}
+	assert.Equal(t, []string{"plan", "read", "review", "write", "test", "ship"}, order)
+}
+
+func TestPriorityQueue_Serialize(t *testing.T) {
+	pq := NewPriorityQueueFrom([]string{"pear", "apple", "fig", "kiwi"}, func(a, b string) bool { return a < b })
+
+	filename := "test_pq.bin"
+	defer os.Remove(filename)
+	require.NoError(t, pq.Serialize(filename))
+	// A different comparator re-heapifies on load
+	loaded := NewPriorityQueueOf(func(a, b string) bool { return a > b })
+	require.NoError(t, loaded.Deserialize(filename))
+	top, _ := loaded.Peek()
+	assert.Equal(t, "pear", top)
+	assert.ElementsMatch(t, pq.Values(), loaded.Values())
+
+	jsonFile := "test_pq.json"
+	defer os.Remove(jsonFile)
+	require.NoError(t, pq.SerializeJSON(jsonFile))
+	fromJSON := NewPriorityQueueOf(func(a, b string) bool { return a < b })
+	require.NoError(t, fromJSON.DeserializeJSON(jsonFile))
+	top, _ = fromJSON.Peek()
+	assert.Equal(t, "apple", top)
+
+	assert.Error(t, pq.Serialize("/nonexistent/dir/file.bin"))
+	assert.Error(t, pq.Deserialize("/nonexistent/file.bin"))
+	assert.Error(t, pq.SerializeJSON("/nonexistent/dir/file.json"))
+	assert.Error(t, pq.DeserializeJSON("/nonexistent/file.json"))
+	assert.Panics(t, func() { NewPriorityQueueOf[int](nil) })
+}
+
+func TestIndexedPriorityQueue_UpdateRemove(t *testing.T) {
+	pq := NewIndexedPriorityQueueOf[string, int](func(a, b int) bool { return a < b })
+	a := pq.Push("a", 5)
+	b := pq.Push("b", 3)
+	c := pq.Push("c", 8)
+	d := pq.Push("d", 1)
+
+	// Decrease and increase key
+	require.NoError(t, pq.Update(c, 0))
+	require.NoError(t, pq.Update(d, 9))
+	assert.Equal(t, 0, c.Priority())
+	value, priority, err := pq.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "c", value)
+	assert.Equal(t, 0, priority)
+
+	require.NoError(t, pq.Remove(b))
+	assert.False(t, pq.Contains(b))
+	assert.Error(t, pq.Remove(b))
+	assert.Error(t, pq.Update(b, 1))
+	assert.Error(t, pq.Remove(nil))
+
+	var order []string
+	for !pq.IsEmpty() {
+		value, _, err := pq.Pop()
+		require.NoError(t, err)
+		order = append(order, value)
+	}
+	assert.Equal(t, []string{"c", "a", "d"}, order)
+	assert.False(t, pq.Contains(a))
+	_, _, err = pq.Pop()
+	assert.Error(t, err)
+
+	// A handle from another queue is rejected
+	other := NewIndexedPriorityQueueOf[string, int](func(a, b int) bool { return a < b })
+	foreign := other.Push("x", 1)
+	pq.Push("y", 2)
+	assert.Error(t, pq.Update(foreign, 0))
+	assert.Panics(t, func() { NewIndexedPriorityQueueOf[int, int](nil) })
+}
+
+func TestIndexedPriorityQueue_RandomOps(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	pq := NewIndexedPriorityQueue()
+	live := make(map[*PQItem[int, int]]bool)
+
+	for i := 0; i < 5000; i++ {
+		switch op := rng.Intn(10); {
+		case op < 4 || len(live) == 0:
+			live[pq.Push(i, rng.Intn(1000))] = true
+		case op < 7:
+			for item := range live {
+				require.NoError(t, pq.Update(item, rng.Intn(1000)))
+				break
+			}
+		case op < 8:
+			for item := range live {
+				require.NoError(t, pq.Remove(item))
+				delete(live, item)
+				break
+			}
+		default:
+			value, priority, err := pq.Pop()
+			require.NoError(t, err)
+			for item := range live {
+				require.GreaterOrEqual(t, item.Priority(), priority)
+				if item.Value() == value {
+					delete(live, item)
+				}
+			}
+		}
+		require.Equal(t, len(live), pq.Len())
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pq.SerializeTo(&buf))
+	restored := NewIndexedPriorityQueue()
+	require.NoError(t, restored.DeserializeFrom(&buf))
+	last := -1
+	for !restored.IsEmpty() {
+		_, priority, _ := restored.Pop()
+		require.GreaterOrEqual(t, priority, last)
+		last = priority
+	}
+}
+
+func TestIndexedPriorityQueue_SerializeJSON(t *testing.T) {
+	pq := NewIndexedPriorityQueueOf[string, float64](func(a, b float64) bool { return a < b })
+	pq.Push("slow", 2.5)
+	pq.Push("fast", 0.5)
+
+	filename := "test_ipq.json"
+	defer os.Remove(filename)
+	require.NoError(t, pq.SerializeJSON(filename))
+	loaded := NewIndexedPriorityQueueOf[string, float64](func(a, b float64) bool { return a < b })
+	require.NoError(t, loaded.DeserializeJSON(filename))
+	value, priority, _ := loaded.Peek()
+	assert.Equal(t, "fast", value)
+	assert.Equal(t, 0.5, priority)
+
+	binFile := "test_ipq.bin"
+	defer os.Remove(binFile)
+	require.NoError(t, pq.Serialize(binFile))
+	require.NoError(t, loaded.Deserialize(binFile))
+	assert.Equal(t, 2, loaded.Len())
+	assert.Error(t, loaded.Deserialize("/nonexistent/file.bin"))
+	assert.Error(t, loaded.DeserializeJSON("/nonexistent/file.json"))
+}
+
+// ==================== LRUCache Tests ====================
+
+func TestLRUCache_Basic(t *testing.T) {
+	cache := NewLRUCache(3)
+	assert.Equal(t, 3, cache.Cap())
+	assert.True(t, cache.IsEmpty())
+
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	cache.Put(3, 30)
+	assert.Equal(t, []int{3, 2, 1}, cache.Keys())
+
+	value, ok := cache.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 10, value)
+	assert.Equal(t, []int{1, 3, 2}, cache.Keys())
+
+	// Peek neither promotes nor counts
+	value, ok = cache.Peek(2)
+	assert.True(t, ok)
+	assert.Equal(t, 20, value)
+	assert.Equal(t, []int{1, 3, 2}, cache.Keys())
+
+	cache.Put(4, 40)
+	assert.False(t, cache.Contains(2))
+	assert.Equal(t, []int{4, 1, 3}, cache.Keys())
+
+	cache.Put(3, 33)
+	value, _ = cache.Peek(3)
+	assert.Equal(t, 33, value)
+	assert.Equal(t, []int{3, 4, 1}, cache.Keys())
+	assert.Equal(t, 3, cache.Len())
+
+	_, ok = cache.Get(2)
+	assert.False(t, ok)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Evictions: 1}, cache.Stats())
+	assert.Equal(t, 0.5, cache.Stats().HitRate())
+	cache.ResetStats()
+	assert.Equal(t, 0.0, cache.Stats().HitRate())
+
+	assert.True(t, cache.Remove(4))
+	assert.False(t, cache.Remove(4))
+	assert.Equal(t, []int{3, 1}, cache.Keys())
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	_, ok = cache.Peek(3)
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { NewLRUCache(0) })
+}
+
+func TestLRUCache_OnEvict(t *testing.T) {
+	cache := NewLRUCacheOf[string, int](2, nil, nil)
+	var evicted []string
+	cache.SetOnEvict(func(key string, value int) {
+		evicted = append(evicted, fmt.Sprintf("%s=%d", key, value))
+	})
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")
+	cache.Put("c", 3)
+	cache.Put("d", 4)
+	cache.Remove("d")
+	cache.Put("e", 5)
+	cache.Clear()
+	assert.Equal(t, []string{"b=2", "a=1"}, evicted)
+	assert.Equal(t, 2, cache.Stats().Evictions)
+}
+
+func TestLRUCache_RandomOps(t *testing.T) {
+	const capacity = 16
+	cache := NewLRUCache(capacity)
+	rng := rand.New(rand.NewSource(22))
+
+	// Reference model: keys from least to most recently used
+	var order []int
+	values := make(map[int]int)
+	drop := func(key int) {
+		for i, k := range order {
+			if k == key {
+				order = append(order[:i], order[i+1:]...)
+				return
+			}
+		}
+	}
+
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(40)
+		switch rng.Intn(4) {
+		case 0, 1:
+			if _, ok := values[key]; !ok && len(order) == capacity {
+				delete(values, order[0])
+				order = order[1:]
+			}
+			cache.Put(key, i)
+			values[key] = i
+			drop(key)
+			order = append(order, key)
+		case 2:
+			value, ok := cache.Get(key)
+			want, exists := values[key]
+			require.Equal(t, exists, ok)
+			if ok {
+				require.Equal(t, want, value)
+				drop(key)
+				order = append(order, key)
+			}
+		default:
+			_, exists := values[key]
+			require.Equal(t, exists, cache.Remove(key))
+			delete(values, key)
+			drop(key)
+		}
+		require.Equal(t, len(order), cache.Len())
+	}
+
+	keys := cache.Keys()
+	for i, key := range keys {
+		require.Equal(t, order[len(order)-1-i], key)
+	}
+	for key, value := range cache.All() {
+		require.Equal(t, values[key], value)
+	}
+}
+
+// ==================== LFUCache and ARCCache Tests ====================
+
+func TestLFUCache_Basic(t *testing.T) {
+	cache := NewLFUCache(2)
+	var evicted []int
+	cache.SetOnEvict(func(key, value int) { evicted = append(evicted, key) })
+
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	value, ok := cache.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 10, value)
+	assert.Equal(t, 2, cache.Frequency(1))
+	assert.Equal(t, []int{1, 2}, cache.Keys())
+
+	// 2 has the lowest count
+	cache.Put(3, 30)
+	assert.False(t, cache.Contains(2))
+	assert.Equal(t, 0, cache.Frequency(2))
+
+	// Peek counts nothing, so 3 is still the victim
+	cache.Peek(3)
+	cache.Put(4, 40)
+	assert.Equal(t, []int{1, 4}, cache.Keys())
+
+	cache.Get(4)
+	cache.Put(4, 44)
+	assert.Equal(t, 3, cache.Frequency(4))
+	assert.Equal(t, []int{4, 1}, cache.Keys())
+	cache.Put(5, 50)
+	assert.Equal(t, []int{2, 3, 1}, evicted)
+	assert.Equal(t, []int{4, 5}, cache.Keys())
+	value, _ = cache.Peek(4)
+	assert.Equal(t, 44, value)
+
+	_, ok = cache.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Evictions: 3}, cache.Stats())
+
+	assert.True(t, cache.Remove(4))
+	assert.False(t, cache.Remove(4))
+	cache.Put(6, 60)
+	cache.Put(7, 70)
+	assert.Equal(t, []int{7, 6}, cache.Keys())
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Clear()
+	assert.True(t, cache.IsEmpty())
+	cache.Put(8, 80)
+	assert.Equal(t, []int{8}, cache.Keys())
+	assert.Panics(t, func() { NewLFUCache(-1) })
+}
+
+func TestLFUCache_RandomOps(t *testing.T) {
+	const capacity = 12
+	cache := NewLFUCache(capacity)
+	rng := rand.New(rand.NewSource(23))
+
+	// Reference model with a linear scan for the victim
+	type modelEntry struct{ value, freq, used int }
+	model := make(map[int]*modelEntry)
+
+	for tick := 0; tick < 5000; tick++ {
+		key := rng.Intn(30)
+		switch rng.Intn(4) {
+		case 0, 1:
+			if e, ok := model[key]; ok {
+				e.value, e.used = tick, tick
+				e.freq++
+			} else {
+				if len(model) == capacity {
+					victim, best := 0, (*modelEntry)(nil)
+					for k, e := range model {
+						if best == nil || e.freq < best.freq || (e.freq == best.freq && e.used < best.used) {
+							victim, best = k, e
+						}
+					}
+					delete(model, victim)
+				}
+				model[key] = &modelEntry{value: tick, freq: 1, used: tick}
+			}
+			cache.Put(key, tick)
+		case 2:
+			value, ok := cache.Get(key)
+			e, exists := model[key]
+			require.Equal(t, exists, ok)
+			if ok {
+				require.Equal(t, e.value, value)
+				e.freq++
+				e.used = tick
+			}
+		default:
+			_, exists := model[key]
+			require.Equal(t, exists, cache.Remove(key))
+			delete(model, key)
+		}
+		require.Equal(t, len(model), cache.Len())
+	}
+
+	for key, e := range model {
+		require.Equal(t, e.freq, cache.Frequency(key))
+	}
+}
+
+func TestARCCache_Basic(t *testing.T) {
+	cache := NewARCCache(3)
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	cache.Put(3, 30)
+	assert.Equal(t, []int{3, 2, 1}, cache.Keys())
+
+	// A hit moves the key from t1 to t2
+	value, ok := cache.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 10, value)
+	assert.Equal(t, []int{1, 3, 2}, cache.Keys())
+	assert.Equal(t, 1, cache.t2.Len())
+
+	var evicted []int
+	cache.SetOnEvict(func(key, value int) { evicted = append(evicted, key) })
+	cache.Put(4, 40)
+	assert.Equal(t, []int{2}, evicted)
+	assert.False(t, cache.Contains(2))
+	assert.Equal(t, 3, cache.Len())
+
+	// 2 is a ghost now: Get misses, Put brings it back into t2 and favours
+	// recency
+	_, ok = cache.Get(2)
+	assert.False(t, ok)
+	_, ok = cache.Peek(2)
+	assert.False(t, ok)
+	assert.False(t, cache.Remove(2))
+	cache.Put(2, 22)
+	assert.Equal(t, 1, cache.Target())
+	value, ok = cache.Peek(2)
+	assert.True(t, ok)
+	assert.Equal(t, 22, value)
+	assert.Equal(t, 3, cache.Len())
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Evictions: 2}, cache.Stats())
+
+	assert.True(t, cache.Remove(2))
+	assert.Equal(t, 2, cache.Len())
+	cache.Clear()
+	assert.True(t, cache.IsEmpty())
+	assert.Equal(t, 0, cache.Target())
+	assert.Equal(t, 0, cache.b1.Len()+cache.b2.Len())
+	assert.Panics(t, func() { NewARCCache(0) })
+}
+
+func TestARCCache_ScanResistance(t *testing.T) {
+	const capacity = 100
+	arc := NewARCCache(capacity)
+	lru := NewLRUCache(capacity)
+	caches := []Cache[int, int]{arc, lru}
+
+	for _, cache := range caches {
+		for round := 0; round < 2; round++ {
+			for key := 0; key < 50; key++ {
+				if _, ok := cache.Get(key); !ok {
+					cache.Put(key, key)
+				}
+			}
+		}
+		// One pass over many keys that are never used again
+		for key := 1000; key < 3000; key++ {
+			cache.Put(key, key)
+		}
+	}
+
+	for key := 0; key < 50; key++ {
+		assert.True(t, arc.Contains(key), "ARC lost hot key %d", key)
+		assert.False(t, lru.Contains(key))
+	}
+
+	// The scan keys ended up in b1, so asking for one again adapts p
+	assert.True(t, arc.Contains(2950))
+	assert.False(t, arc.Contains(2920))
+	arc.Put(2920, 0)
+	assert.Greater(t, arc.Target(), 0)
+}
+
+func TestARCCache_RandomOps(t *testing.T) {
+	const capacity = 8
+	cache := NewARCCache(capacity)
+	rng := rand.New(rand.NewSource(230))
+	last := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		key := rng.Intn(24)
+		switch rng.Intn(6) {
+		case 0, 1, 2:
+			cache.Put(key, i)
+			last[key] = i
+		case 3, 4:
+			if value, ok := cache.Get(key); ok {
+				require.Equal(t, last[key], value)
+			}
+		default:
+			cache.Remove(key)
+		}
+
+		t1, t2, b1, b2 := cache.t1.Len(), cache.t2.Len(), cache.b1.Len(), cache.b2.Len()
+		require.LessOrEqual(t, t1+t2, capacity)
+		require.LessOrEqual(t, t1+b1, capacity)
+		require.LessOrEqual(t, t1+t2+b1+b2, 2*capacity)
+		require.Equal(t, t1+t2+b1+b2, cache.index.Len())
+		require.GreaterOrEqual(t, cache.Target(), 0)
+		require.LessOrEqual(t, cache.Target(), capacity)
+	}
+}
+
+func TestCaches_Common(t *testing.T) {
+	const capacity = 32
+	caches := map[string]Cache[int, int]{
+		"LRU": NewLRUCache(capacity),
+		"LFU": NewLFUCache(capacity),
+		"ARC": NewARCCache(capacity),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			evicted := 0
+			cache.SetOnEvict(func(key, value int) {
+				assert.Equal(t, key*10, value)
+				evicted++
+			})
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 2000; i++ {
+				key := rng.Intn(100)
+				if value, ok := cache.Get(key); ok {
+					require.Equal(t, key*10, value)
+				} else {
+					cache.Put(key, key*10)
+				}
+				require.LessOrEqual(t, cache.Len(), cache.Cap())
+			}
+
+			stats := cache.Stats()
+			assert.Equal(t, 2000, stats.Hits+stats.Misses)
+			assert.Equal(t, evicted, stats.Evictions)
+			assert.Equal(t, capacity, cache.Len())
+			assert.Greater(t, stats.HitRate(), 0.0)
+
+			cache.ResetStats()
+			cache.Clear()
+			assert.Equal(t, 0, cache.Len())
+			assert.Equal(t, CacheStats{}, cache.Stats())
+		})
+	}
+}
+
+// ==================== SkipList Tests ====================
+
+// checkSkipList verifies that every level is sorted and that each node on
+// a level is also on all the levels below it.
+func checkSkipList[K cmp.Ordered, V any](t *testing.T, s *SkipList[K, V]) {
+	t.Helper()
+	count := 0
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		count++
+		require.LessOrEqual(t, len(n.next), s.level)
+	}
+	require.Equal(t, s.size, count)
+	for i := 1; i < s.level; i++ {
+		lower := s.head.next[i-1]
+		for n := s.head.next[i]; n != nil; n = n.next[i] {
+			for lower != n {
+				require.NotNil(t, lower, "level %d node missing below", i)
+				lower = lower.next[i-1]
+			}
+			if next := n.next[i]; next != nil {
+				require.Less(t, n.key, next.key)
+			}
+		}
+	}
+	if s.level > 1 {
+		require.NotNil(t, s.head.next[s.level-1])
+	}
+}
+
+func TestSkipList_Basic(t *testing.T) {
+	s := NewSkipList()
+	_, ok := s.Min()
+	assert.False(t, ok)
+	_, ok = s.Max()
+	assert.False(t, ok)
+	_, ok = s.Floor(5)
+	assert.False(t, ok)
+
+	for _, k := range []int{40, 10, 70, -5, 25, 10} {
+		s.Insert(k)
+	}
+	assert.Equal(t, 5, s.Len())
+	assert.Equal(t, []int{-5, 10, 25, 40, 70}, s.Values())
+	assert.True(t, s.Find(25))
+	assert.False(t, s.Find(26))
+	minKey, _ := s.Min()
+	maxKey, _ := s.Max()
+	assert.Equal(t, -5, minKey)
+	assert.Equal(t, 70, maxKey)
+
+	floor, ok := s.Floor(30)
+	assert.True(t, ok)
+	assert.Equal(t, 25, floor)
+	ceiling, ok := s.Ceiling(30)
+	assert.True(t, ok)
+	assert.Equal(t, 40, ceiling)
+	_, ok = s.Ceiling(71)
+	assert.False(t, ok)
+
+	s.Remove(10)
+	s.Remove(11)
+	assert.Equal(t, []int{-5, 25, 40, 70}, s.Values())
+	checkSkipList(t, s)
+
+	s.Clear()
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, 1, s.Level())
+	assert.Empty(t, s.Values())
+}
+
+func TestSkipList_MatchesAVLTree(t *testing.T) {
+	s := NewSkipListOf[int, int]()
+	s.SetSeed(24)
+	tree := NewAVLTreeOf[int, int]()
+	rng := rand.New(rand.NewSource(24))
+
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(1000)
+		switch rng.Intn(3) {
+		case 0, 1:
+			s.Put(key, i)
+			tree.Put(key, i)
+		default:
+			require.Equal(t, tree.Delete(key), s.Delete(key))
+		}
+	}
+	checkSkipList(t, s)
+	require.Equal(t, tree.Values(), s.Values())
+
+	for key := -1; key <= 1001; key += 7 {
+		got, ok := s.Floor(key)
+		want, wantOK := tree.Floor(key)
+		require.Equal(t, wantOK, ok)
+		require.Equal(t, want, got)
+		got, ok = s.Ceiling(key)
+		want, wantOK = tree.Ceiling(key)
+		require.Equal(t, wantOK, ok)
+		require.Equal(t, want, got)
+		value, ok := s.Get(key)
+		wantValue, wantOK := tree.Get(key)
+		require.Equal(t, wantOK, ok)
+		require.Equal(t, wantValue, value)
+	}
+
+	for _, r := range [][2]int{{100, 200}, {-10, 5}, {990, 2000}, {300, 299}} {
+		var got, want []int
+		for k, v := range s.Range(r[0], r[1]) {
+			got = append(got, k, v)
+		}
+		for k, v := range tree.Range(r[0], r[1]) {
+			want = append(want, k, v)
+		}
+		require.Equal(t, want, got, "Range(%d, %d)", r[0], r[1])
+	}
+}
+
+func TestSkipList_SeedReproducible(t *testing.T) {
+	shape := func(seed uint64) []int {
+		s := NewSkipList()
+		s.SetSeed(seed)
+		for i := 0; i < 500; i++ {
+			s.Insert(i)
+		}
+		heights := make([]int, 0, s.Len())
+		for n := s.head.next[0]; n != nil; n = n.next[0] {
+			heights = append(heights, len(n.next))
+		}
+		return heights
+	}
+
+	assert.Equal(t, shape(1), shape(1))
+	assert.NotEqual(t, shape(1), shape(2))
+
+	// Sequential inserts still give a logarithmic height
+	s := NewSkipList()
+	s.SetSeed(3)
+	for i := 0; i < 100000; i++ {
+		s.Insert(i)
+	}
+	checkSkipList(t, s)
+	assert.LessOrEqual(t, s.Level(), 16)
+}
+
+func TestSkipList_ModifyWhileIterating(t *testing.T) {
+	s := NewSkipList()
+	for i := 0; i < 10; i++ {
+		s.Insert(i * 10)
+	}
+
+	var seen []int
+	for key := range s.All() {
+		seen = append(seen, key)
+		switch key {
+		case 0:
+			s.Remove(0)
+			s.Remove(10)
+			s.Insert(15)
+			s.Insert(-5) // behind the cursor, not yielded
+		case 40:
+			s.Remove(50)
+			s.Remove(60)
+			s.Insert(45)
+		}
+	}
+	assert.Equal(t, []int{0, 15, 20, 30, 40, 45, 70, 80, 90}, seen)
+	assert.Equal(t, []int{-5, 15, 20, 30, 40, 45, 70, 80, 90}, s.Values())
+
+	// Deleting every key of a range scan as it goes
+	for key := range s.Range(20, 70) {
+		s.Remove(key)
+	}
+	assert.Equal(t, []int{-5, 15, 80, 90}, s.Values())
+	checkSkipList(t, s)
+}
+
+func TestSkipList_Serialize(t *testing.T) {
+	s := NewSkipListOf[string, int]()
+	for i, word := range []string{"pear", "apple", "fig", "kiwi"} {
+		s.Put(word, i)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, s.SerializeTo(&buf))
+	loaded := NewSkipListOf[string, int]()
+	require.NoError(t, loaded.DeserializeFrom(&buf))
+	assert.Equal(t, []string{"apple", "fig", "kiwi", "pear"}, loaded.Values())
+	value, ok := loaded.Get("fig")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	filename := "test_skiplist.json"
+	defer os.Remove(filename)
+	require.NoError(t, s.SerializeJSON(filename))
+	loaded = NewSkipListOf[string, int]()
+	require.NoError(t, loaded.DeserializeJSON(filename))
+	value, _ = loaded.Get("kiwi")
+	assert.Equal(t, 3, value)
+	assert.Equal(t, 4, loaded.Len())
+
+	// Sets leave the values out, like AVLTree
+	set := NewSkipList()
+	set.Insert(3)
+	set.Insert(1)
+	setFile := "test_skipset.json"
+	defer os.Remove(setFile)
+	require.NoError(t, set.SerializeJSON(setFile))
+	data, err := os.ReadFile(setFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "values")
+
+	binFile := "test_skipset.bin"
+	defer os.Remove(binFile)
+	require.NoError(t, set.Serialize(binFile))
+	loadedSet := NewSkipList()
+	require.NoError(t, loadedSet.Deserialize(binFile))
+	assert.Equal(t, []int{1, 3}, loadedSet.Values())
+
+	assert.Error(t, loadedSet.Deserialize("/nonexistent/file.bin"))
+	assert.Error(t, loadedSet.DeserializeJSON("/nonexistent/file.json"))
+	assert.Error(t, set.Serialize("/invalid/path/file.bin"))
+}
+
+// ==================== RedBlackTree Tests ====================
+
+// checkRedBlack verifies the ordering, the parent links and both coloring
+// rules, and returns the black height.
+func checkRedBlack[K cmp.Ordered, V any](t *testing.T, tree *RedBlackTree[K, V]) int {
+	t.Helper()
+	require.False(t, isRed(tree.root), "red root")
+	count := 0
+	var walk func(n, parent *rbNode[K, V]) int
+	walk = func(n, parent *rbNode[K, V]) int {
+		if n == nil {
+			return 1
+		}
+		count++
+		require.Equal(t, parent, n.parent, "parent link of %v", n.key)
+		if n.red {
+			require.False(t, isRed(n.left) || isRed(n.right), "red %v has a red child", n.key)
+		}
+		if n.left != nil {
+			require.Less(t, n.left.key, n.key)
+		}
+		if n.right != nil {
+			require.Greater(t, n.right.key, n.key)
+		}
+		left, right := walk(n.left, n), walk(n.right, n)
+		require.Equal(t, left, right, "black height differs below %v", n.key)
+		if n.red {
+			return left
+		}
+		return left + 1
+	}
+	height := walk(tree.root, nil)
+	require.Equal(t, tree.size, count)
+	return height
+}
+
+func TestRedBlackTree_Basic(t *testing.T) {
+	tree := NewRedBlackTree()
+	_, ok := tree.Min()
+	assert.False(t, ok)
+	_, ok = tree.Max()
+	assert.False(t, ok)
+
+	for _, k := range []int{40, 10, 70, -5, 25, 10} {
+		tree.Insert(k)
+	}
+	checkRedBlack(t, tree)
+	assert.Equal(t, 5, tree.Len())
+	assert.Equal(t, []int{-5, 10, 25, 40, 70}, tree.Values())
+	assert.True(t, tree.Find(25))
+	assert.False(t, tree.Find(26))
+	minKey, _ := tree.Min()
+	maxKey, _ := tree.Max()
+	assert.Equal(t, -5, minKey)
+	assert.Equal(t, 70, maxKey)
+
+	floor, ok := tree.Floor(30)
+	assert.True(t, ok)
+	assert.Equal(t, 25, floor)
+	_, ok = tree.Floor(-6)
+	assert.False(t, ok)
+	ceiling, ok := tree.Ceiling(30)
+	assert.True(t, ok)
+	assert.Equal(t, 40, ceiling)
+	_, ok = tree.Ceiling(71)
+	assert.False(t, ok)
+
+	tree.Remove(10)
+	tree.Remove(11)
+	assert.Equal(t, []int{-5, 25, 40, 70}, tree.Values())
+	checkRedBlack(t, tree)
+
+	tree.Clear()
+	assert.True(t, tree.IsEmpty())
+	assert.Equal(t, 0, tree.Height())
+}
+
+func TestRedBlackTree_MatchesAVLTree(t *testing.T) {
+	tree := NewRedBlackTreeOf[int, int]()
+	avl := NewAVLTreeOf[int, int]()
+	rng := rand.New(rand.NewSource(25))
+
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(800)
+		switch rng.Intn(3) {
+		case 0, 1:
+			tree.Put(key, i)
+			avl.Put(key, i)
+		default:
+			require.Equal(t, avl.Delete(key), tree.Delete(key))
+		}
+		if i%250 == 0 {
+			checkRedBlack(t, tree)
+		}
+	}
+	checkRedBlack(t, tree)
+	require.Equal(t, avl.Values(), tree.Values())
+
+	for key := -1; key <= 801; key += 9 {
+		got, ok := tree.Floor(key)
+		want, wantOK := avl.Floor(key)
+		require.Equal(t, wantOK, ok)
+		require.Equal(t, want, got)
+		got, ok = tree.Ceiling(key)
+		want, wantOK = avl.Ceiling(key)
+		require.Equal(t, wantOK, ok)
+		require.Equal(t, want, got)
+		value, ok := tree.Get(key)
+		wantValue, wantOK := avl.Get(key)
+		require.Equal(t, wantOK, ok)
+		require.Equal(t, wantValue, value)
+	}
+
+	for _, r := range [][2]int{{100, 200}, {-10, 5}, {790, 2000}, {300, 299}} {
+		var got, want []int
+		for k, v := range tree.Range(r[0], r[1]) {
+			got = append(got, k, v)
+		}
+		for k, v := range avl.Range(r[0], r[1]) {
+			want = append(want, k, v)
+		}
+		require.Equal(t, want, got, "Range(%d, %d)", r[0], r[1])
+	}
+
+	// Deleting everything exercises every fixup case down to the root
+	for _, key := range tree.Values() {
+		tree.Remove(key)
+	}
+	checkRedBlack(t, tree)
+	assert.True(t, tree.IsEmpty())
+}
+
+func TestRedBlackTree_Stats(t *testing.T) {
+	tree := NewRedBlackTree()
+	avl := NewAVLTree()
+	for _, k := range []int{1, 2, 3} {
+		tree.Insert(k)
+		avl.Insert(k)
+	}
+	// Painting the first root black, then one rotation for 1, 2, 3
+	assert.Equal(t, TreeStats{Rotations: 1, Recolorings: 3}, tree.Stats())
+	assert.Equal(t, TreeStats{Rotations: 1}, avl.Stats())
+
+	// Removing the black root 2 moves its red successor 3 up and gives it
+	// the root's color, which is not counted as a recoloring
+	tree.ResetStats()
+	tree.Remove(2)
+	checkRedBlack(t, tree)
+	assert.Equal(t, TreeStats{}, tree.Stats())
+	tree.Insert(2)
+
+	tree.ResetStats()
+	avl.ResetStats()
+	assert.Equal(t, TreeStats{}, tree.Stats())
+	for i := 4; i < 1000; i++ {
+		tree.Insert(i)
+		avl.Insert(i)
+	}
+	checkRedBlack(t, tree)
+	assert.Greater(t, tree.Stats().Recolorings, 0)
+	assert.Greater(t, avl.Stats().Rotations, 0)
+	assert.Zero(t, avl.Stats().Recolorings)
+
+	// The height stays within 2*log2(n+1)
+	assert.LessOrEqual(t, tree.Height(), 2*bits.Len(uint(tree.Len()+1)))
+
+	// Existing keys cost nothing
+	before := tree.Stats()
+	tree.Insert(500)
+	tree.Remove(5000)
+	assert.Equal(t, before, tree.Stats())
+}
+
+func TestRedBlackTree_Serialize(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 8, 100} {
+		tree := NewRedBlackTreeOf[int, string]()
+		for i := 0; i < n; i++ {
+			tree.Put(i*3, fmt.Sprint(i))
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, tree.SerializeTo(&buf))
+		loaded := NewRedBlackTreeOf[int, string]()
+		loaded.Put(-1, "stale")
+		loaded.ResetStats()
+		require.NoError(t, loaded.DeserializeFrom(&buf))
+		checkRedBlack(t, loaded)
+		assert.Equal(t, tree.Values(), loaded.Values())
+		assert.Equal(t, TreeStats{}, loaded.Stats(), "loading %d keys", n)
+		if n > 0 {
+			value, ok := loaded.Get(3 * (n - 1))
+			assert.True(t, ok)
+			assert.Equal(t, fmt.Sprint(n-1), value)
+		}
+
+		// The loaded tree stays valid under further updates
+		for i := 0; i < n; i += 2 {
+			loaded.Remove(i * 3)
+			loaded.Insert(i*3 + 1)
+		}
+		checkRedBlack(t, loaded)
+	}
+
+	set := NewRedBlackTree()
+	for _, k := range []int{5, 1, 3} {
+		set.Insert(k)
+	}
+	filename := "test_rbtree.json"
+	defer os.Remove(filename)
+	require.NoError(t, set.SerializeJSON(filename))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "values")
+	loaded := NewRedBlackTree()
+	require.NoError(t, loaded.DeserializeJSON(filename))
+	assert.Equal(t, []int{1, 3, 5}, loaded.Values())
+	checkRedBlack(t, loaded)
+
+	// Keys out of order are inserted one by one, still without counting
+	loaded.ResetStats()
+	require.NoError(t, os.WriteFile(filename, []byte(`{"keys":[9,2,7,2]}`), 0o644))
+	require.NoError(t, loaded.DeserializeJSON(filename))
+	assert.Equal(t, []int{2, 7, 9}, loaded.Values())
+	assert.Equal(t, TreeStats{}, loaded.Stats())
+	checkRedBlack(t, loaded)
+	require.NoError(t, os.WriteFile(filename, []byte(`{"keys":[1,2],"values":[1]}`), 0o644))
+	assert.Error(t, NewRedBlackTreeOf[int, int]().DeserializeJSON(filename))
+
+	binFile := "test_rbtree.bin"
+	defer os.Remove(binFile)
+	require.NoError(t, set.Serialize(binFile))
+	require.NoError(t, loaded.Deserialize(binFile))
+	assert.Equal(t, []int{1, 3, 5}, loaded.Values())
+	assert.Error(t, loaded.Deserialize("/nonexistent/file.bin"))
+	assert.Error(t, loaded.DeserializeJSON("/nonexistent/file.json"))
+	assert.Error(t, set.SerializeJSON("/invalid/path/file.json"))
+}
+
 // ==================== Helper Functions ====================
 
 func writeUint64(file *os.File, val uint64) error {